@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var infoHashRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Cache manages the torrents stored by the engine in directories named after
+// their infohash, evicting the least recently used ones above MaxSize.
+type Cache struct {
+	Directory string
+	// MaxSize is in bytes, 0 disables the eviction
+	MaxSize uint64
+	// Pinned tells if a torrent of the directory must be kept, like the ones
+	// in the library
+	Pinned func(directory string, infoHash string) bool
+}
+
+// Name of the bolt database in which the engine records the completed pieces,
+// locked by the process streaming from the cache
+const completionDatabase = ".torrent.bolt.db"
+
+var ErrInUse = errors.New("the cache is used by another nyaa-cli streaming a torrent, close it first")
+
+type Entry struct {
+	InfoHash string
+	Size     uint64
+	LastUsed time.Time
+	Pinned   bool
+}
+
+// Entries lists the cached torrents, least recently used first.
+func (c *Cache) Entries() ([]Entry, error) {
+	dirEntries, err := os.ReadDir(c.Directory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || !infoHashRegex.MatchString(dirEntry.Name()) {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		size, err := directorySize(filepath.Join(c.Directory, dirEntry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, Entry{
+			InfoHash: dirEntry.Name(),
+			Size:     size,
+			LastUsed: info.ModTime(),
+			Pinned:   c.Pinned != nil && c.Pinned(c.Directory, dirEntry.Name()),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	return entries, nil
+}
+
+// Touch marks the torrent as used now.
+func (c *Cache) Touch(infoHash string) error {
+	now := time.Now()
+
+	err := os.Chtimes(filepath.Join(c.Directory, infoHash), now, now)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Evict removes the least recently used torrents until the cache fits in
+// MaxSize once the active torrent is fully downloaded, length being the bytes
+// it will take. Pinned torrents and the active one are never removed.
+func (c *Cache) Evict(active string, length uint64) error {
+	if c.MaxSize == 0 {
+		return nil
+	}
+
+	entries, err := c.Entries()
+	if err != nil {
+		return err
+	}
+
+	// The data of the active torrent already on disk is part of its length
+	total := length
+	for _, entry := range entries {
+		if entry.InfoHash != active {
+			total += entry.Size
+		}
+	}
+
+	for _, entry := range entries {
+		if total <= c.MaxSize {
+			break
+		}
+
+		if entry.Pinned || entry.InfoHash == active {
+			continue
+		}
+
+		if err := c.remove(entry); err != nil {
+			return err
+		}
+
+		total -= entry.Size
+	}
+
+	return nil
+}
+
+// Clear removes every torrent that isn't pinned or active. It fails with
+// ErrInUse while another process streams from the cache, as the torrent it
+// plays can't be known.
+func (c *Cache) Clear(active ...string) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.Entries()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Pinned || contains(active, entry.InfoHash) {
+			continue
+		}
+
+		if err := c.remove(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lock takes the completion database so no process starts streaming while the
+// cache is modified.
+func (c *Cache) lock() (func(), error) {
+	path := filepath.Join(c.Directory, completionDatabase)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return func() {}, nil
+	}
+
+	db, err := bbolt.Open(path, 0o660, &bbolt.Options{Timeout: time.Second})
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil, ErrInUse
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { db.Close() }, nil
+}
+
+func (c *Cache) remove(entry Entry) error {
+	return os.RemoveAll(filepath.Join(c.Directory, entry.InfoHash))
+}
+
+func directorySize(directory string) (uint64, error) {
+	size := uint64(0)
+
+	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
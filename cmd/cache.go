@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/quantumsheep/nyaa-cli/cache"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/urfave/cli/v2"
+)
+
+var CacheCmd = &cli.Command{
+	Name:  "cache",
+	Usage: "Manage the data directory used while streaming",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "stats",
+			Usage: "Print the size of the cached torrents",
+			Action: func(c *cli.Context) error {
+				torrentCache, err := loadCache(c)
+				if err != nil {
+					return err
+				}
+
+				entries, err := torrentCache.Entries()
+				if err != nil {
+					return err
+				}
+
+				total := uint64(0)
+				for _, entry := range entries {
+					pinned := ""
+					if entry.Pinned {
+						pinned = "pinned"
+					}
+
+					fmt.Printf("%s  %10s  %s  %s\n", entry.InfoHash, humanize.Bytes(entry.Size), entry.LastUsed.Format("2006-01-02 15:04"), pinned)
+					total += entry.Size
+				}
+
+				limit := "unlimited"
+				if torrentCache.MaxSize > 0 {
+					limit = humanize.Bytes(torrentCache.MaxSize)
+				}
+
+				fmt.Printf("\n%s: %d torrents, %s / %s\n", torrentCache.Directory, len(entries), humanize.Bytes(total), limit)
+				return nil
+			},
+		},
+		{
+			Name:  "clear",
+			Usage: "Remove every cached torrent that isn't in the library",
+			Action: func(c *cli.Context) error {
+				torrentCache, err := loadCache(c)
+				if err != nil {
+					return err
+				}
+
+				return torrentCache.Clear()
+			},
+		},
+	},
+}
+
+func loadCache(c *cli.Context) (*cache.Cache, error) {
+	cfg := getConfig(c)
+
+	directory, err := cfg.StreamingDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize, err := cfg.CacheMaxSize()
+	if err != nil {
+		return nil, err
+	}
+
+	lib, err := library.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache.Cache{
+		Directory: directory,
+		MaxSize:   maxSize,
+		Pinned:    lib.Pins,
+	}, nil
+}
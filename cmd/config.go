@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+	"github.com/urfave/cli/v2"
+)
+
+var ConfigCmd = &cli.Command{
+	Name:  "config",
+	Usage: "Print the effective configuration, without passwords, and the path of the configuration file",
+	Action: func(c *cli.Context) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("# %s\n", path)
+		return getConfig(c).Redacted().Write(os.Stdout)
+	},
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/quantumsheep/nyaa-cli/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var LibraryCmd = &cli.Command{
+	Name:  "library",
+	Usage: "Manage the downloaded videos of --dir",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List the videos of the library",
+			Action: func(c *cli.Context) error {
+				lib, err := loadLibrary(c)
+				if err != nil {
+					return err
+				}
+
+				for i, item := range lib.Items {
+					fmt.Printf("%4d  %-40s %10s  %s\n", i+1, item.Label(), humanize.Bytes(uint64(item.Size)), item.Path)
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:      "play",
+			Usage:     "Play a video of the library",
+			ArgsUsage: "<number>",
+			Action: func(c *cli.Context) error {
+				lib, err := loadLibrary(c)
+				if err != nil {
+					return err
+				}
+
+				index, err := libraryIndex(c, lib)
+				if err != nil {
+					return err
+				}
+
+				cfg := getConfig(c)
+				item := lib.Items[index]
+
+				return utils.RunVideoPlayer(utils.VideoPlayerConfig{
+					VideoPlayer: cfg.Player,
+					Url:         item.Path,
+					Name:        filepath.Base(item.Path),
+					OnTop:       true,
+					Fullscreen:  cfg.Fullscreen,
+				})
+			},
+		},
+		{
+			Name:      "rm",
+			Usage:     "Delete a video of the library",
+			ArgsUsage: "<number>",
+			Action: func(c *cli.Context) error {
+				lib, err := loadLibrary(c)
+				if err != nil {
+					return err
+				}
+
+				index, err := libraryIndex(c, lib)
+				if err != nil {
+					return err
+				}
+
+				return lib.Remove(index)
+			},
+		},
+	},
+}
+
+func loadLibrary(c *cli.Context) (*library.Library, error) {
+	lib, err := library.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	directory, err := getConfig(c).LibraryDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib, lib.Scan(directory)
+}
+
+// libraryIndex reads the item number given as argument, as printed by the
+// list command.
+func libraryIndex(c *cli.Context, lib *library.Library) (int, error) {
+	number, err := strconv.Atoi(c.Args().First())
+	if err != nil {
+		return 0, fmt.Errorf("expected the number of a library item, got %q", c.Args().First())
+	}
+
+	if number < 1 || number > len(lib.Items) {
+		return 0, fmt.Errorf("no library item #%d", number)
+	}
+
+	return number - 1, nil
+}
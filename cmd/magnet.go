@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/quantumsheep/nyaa-cli/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var MagnetCmd = &cli.Command{
+	Name:      "magnet",
+	Usage:     "Print the magnet link of a torrent",
+	ArgsUsage: "<id>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "infohash",
+			Usage: "print the infohash instead of the magnet link",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		id := strings.TrimSpace(c.Args().First())
+		if id == "" {
+			return fmt.Errorf("expected the id of a torrent")
+		}
+
+		cfg := getConfig(c)
+
+		magnet, err := utils.Magnet(cfg.Provider, utils.TorrentURL(cfg.Provider, id), "", "")
+		if err != nil {
+			return err
+		}
+
+		if c.Bool("infohash") {
+			m, err := metainfo.ParseMagnetUri(magnet)
+			if err != nil {
+				return err
+			}
+
+			magnet = m.InfoHash.HexString()
+		}
+
+		fmt.Println(magnet)
+		return nil
+	},
+}
@@ -3,14 +3,12 @@ package cmd
 import (
 	"strings"
 
+	"github.com/quantumsheep/nyaa-cli/config"
 	"github.com/quantumsheep/nyaa-cli/ui"
+	"github.com/quantumsheep/nyaa-cli/utils"
 	"github.com/urfave/cli/v2"
 )
 
-var allowedVideoPlayers = []string{
-	"vlc",
-}
-
 var RootCmd = &cli.App{
 	Name:  "nyaa",
 	Usage: "Use nyaa.si from the CLI",
@@ -18,8 +16,15 @@ var RootCmd = &cli.App{
 		&cli.StringFlag{
 			Name:    "dir",
 			Aliases: []string{"d"},
-			Usage:   "directory used to store the torrents",
-			Value:   ".",
+			Usage:   "directory used to store the torrents and indexed by the library (default: the current directory)",
+		},
+		&cli.StringFlag{
+			Name:  "data-dir",
+			Usage: "directory used by the torrent engine while streaming (default: the user cache directory)",
+		},
+		&cli.StringFlag{
+			Name:  "cache-size",
+			Usage: "maximum size of the data directory, 0 disables the limit (default: \"10GB\")",
 		},
 		&cli.BoolFlag{
 			Name:  "fullscreen",
@@ -27,15 +32,211 @@ var RootCmd = &cli.App{
 		},
 		&cli.StringFlag{
 			Name:  "player",
-			Usage: "video player to run the videos on. available options: " + strings.Join(allowedVideoPlayers, ", "),
-			Value: "vlc",
+			Usage: "video player to run the videos on. available options: " + strings.Join(utils.VideoPlayers, ", ") + " (default: \"vlc\")",
+		},
+		&cli.StringFlag{
+			Name:  "subtitles-dir",
+			Usage: "directory searched for subtitles matching the played episode",
+		},
+		&cli.IntFlag{
+			Name:  "port",
+			Usage: "port of the local streaming server (default: 3001)",
+		},
+		&cli.StringFlag{
+			Name:  "provider",
+			Usage: "site to search on. available options: nyaa, sukebei (default: \"nyaa\")",
+		},
+		&cli.StringFlag{
+			Name:  "category",
+			Usage: "default search category (default: \"anime-eng\" on nyaa, \"all\" on sukebei)",
+		},
+		&cli.StringFlag{
+			Name:  "filter",
+			Usage: "default search filter. available options: no-filter, no-remakes, trusted-only (default: \"no-filter\")",
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Usage: "default search sort. available options: date, downloads, size, seeders, leechers, comments (default: \"date\")",
+		},
+		&cli.StringFlag{
+			Name:  "order",
+			Usage: "default search order. available options: desc, asc (default: \"desc\")",
+		},
+		&cli.StringFlag{
+			Name:  "columns",
+			Usage: "comma-separated columns of the results table. available options: id, saved, watched, size, date, seeders, leechers, downloads, trusted, remake, category, resolution, episode, name",
+		},
+		&cli.StringFlag{
+			Name:  "keymap",
+			Usage: "preset of key bindings. available options: default, vim, emacs (default: \"default\")",
+		},
+		&cli.StringFlag{
+			Name:  "theme",
+			Usage: "colors of the interface. available options: dark, light, solarized, high-contrast (default: \"dark\")",
 		},
+		&cli.StringFlag{
+			Name:  "proxy",
+			Usage: "HTTP or SOCKS5 proxy used to reach nyaa, like \"socks5://localhost:1080\"",
+		},
+		&cli.StringFlag{
+			Name:  "client",
+			Usage: "profile of the remote torrent client to send torrents to",
+		},
+		&cli.StringFlag{
+			Name:  "saved",
+			Usage: "start with the results of the given saved search",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		applyFlags(c, cfg)
+
+		c.App.Metadata["config"] = cfg
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		// The key bindings depend on the actions of the interface, they are
+		// checked along with the configuration
+		if _, err := ui.BuildKeymap(cfg.Keymap, cfg.Keys); err != nil {
+			return err
+		}
+
+		httpOptions, err := cfg.HTTPOptions()
+		if err != nil {
+			return err
+		}
+
+		return utils.ConfigureHTTP(httpOptions)
 	},
 	Action: func(c *cli.Context) error {
+		cfg := getConfig(c)
+
+		dataDirectory, err := cfg.StreamingDirectory()
+		if err != nil {
+			return err
+		}
+
+		cacheSize, err := cfg.CacheMaxSize()
+		if err != nil {
+			return err
+		}
+
+		stallTimeout, err := cfg.StallTimeoutDuration()
+		if err != nil {
+			return err
+		}
+
+		keymap, err := ui.BuildKeymap(cfg.Keymap, cfg.Keys)
+		if err != nil {
+			return err
+		}
+
+		theme, err := ui.LoadTheme(cfg.Theme)
+		if err != nil {
+			return err
+		}
+
+		// The interface tells when sending a torrent without a client
+		clientProfile, _ := cfg.ClientProfile()
+
 		return ui.NewUI(&ui.UIOptions{
-			VideoPlayer:     c.String("player"),
-			Fullscreen:      c.Bool("fullscreen"),
-			OutputDirectory: c.String("dir"),
+			VideoPlayer:        cfg.Player,
+			Fullscreen:         cfg.Fullscreen,
+			OutputDirectory:    cfg.OutputDirectory(),
+			LibraryDirectory:   cfg.Directory,
+			DataDirectory:      dataDirectory,
+			CacheSize:          cacheSize,
+			Port:               cfg.Port,
+			StallTimeout:       stallTimeout,
+			SubtitlesDirectory: cfg.SubtitlesDirectory,
+			Provider:           cfg.Provider,
+			Category:           cfg.SearchCategory(),
+			Filter:             cfg.Filter,
+			SortBy:             cfg.SortBy,
+			OrderBy:            cfg.OrderBy,
+			ClientProfile:      clientProfile,
+			Columns:            cfg.Columns,
+			Keymap:             keymap,
+			Theme:              theme,
+			SavedSearch:        c.String("saved"),
 		}).Run()
 	},
+	Commands: []*cli.Command{
+		ConfigCmd,
+		WatchCmd,
+		LibraryCmd,
+		CacheCmd,
+		MagnetCmd,
+		SendCmd,
+	},
+}
+
+// applyFlags overrides the configuration with the flags explicitly set on the
+// command line.
+func applyFlags(c *cli.Context, cfg *config.Config) {
+	if c.IsSet("dir") {
+		cfg.Directory = c.String("dir")
+	}
+	if c.IsSet("data-dir") {
+		cfg.DataDirectory = c.String("data-dir")
+	}
+	if c.IsSet("cache-size") {
+		cfg.CacheSize = c.String("cache-size")
+	}
+	if c.IsSet("fullscreen") {
+		cfg.Fullscreen = c.Bool("fullscreen")
+	}
+	if c.IsSet("player") {
+		cfg.Player = c.String("player")
+	}
+	if c.IsSet("subtitles-dir") {
+		cfg.SubtitlesDirectory = c.String("subtitles-dir")
+	}
+	if c.IsSet("port") {
+		cfg.Port = c.Int("port")
+	}
+	if c.IsSet("provider") {
+		cfg.Provider = c.String("provider")
+	}
+	if c.IsSet("category") {
+		cfg.Category = c.String("category")
+	}
+	if c.IsSet("filter") {
+		cfg.Filter = c.String("filter")
+	}
+	if c.IsSet("sort") {
+		cfg.SortBy = c.String("sort")
+	}
+	if c.IsSet("order") {
+		cfg.OrderBy = c.String("order")
+	}
+	if c.IsSet("columns") {
+		cfg.Columns = nil
+		for _, column := range strings.Split(c.String("columns"), ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				cfg.Columns = append(cfg.Columns, column)
+			}
+		}
+	}
+	if c.IsSet("keymap") {
+		cfg.Keymap = c.String("keymap")
+	}
+	if c.IsSet("theme") {
+		cfg.Theme = c.String("theme")
+	}
+	if c.IsSet("proxy") {
+		cfg.HTTP.Proxy = c.String("proxy")
+	}
+	if c.IsSet("client") {
+		cfg.Client = c.String("client")
+	}
+}
+
+func getConfig(c *cli.Context) *config.Config {
+	return c.App.Metadata["config"].(*config.Config)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/quantumsheep/nyaa-cli/remote"
+	"github.com/quantumsheep/nyaa-cli/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var SendCmd = &cli.Command{
+	Name:      "send",
+	Usage:     "Send torrents to the remote torrent client",
+	ArgsUsage: "<id>...",
+	Action: func(c *cli.Context) error {
+		if c.NArg() == 0 {
+			return fmt.Errorf("expected the id of a torrent")
+		}
+
+		cfg := getConfig(c)
+
+		profile, err := cfg.ClientProfile()
+		if err != nil {
+			return err
+		}
+
+		client, err := remote.New(profile)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range c.Args().Slice() {
+			link := utils.TorrentURL(cfg.Provider, id)
+			if profile.Magnet {
+				link, err = utils.Magnet(cfg.Provider, link, "", "")
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := client.Add(link); err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+
+			fmt.Printf("Sent %s\n", id)
+		}
+
+		return nil
+	},
+}
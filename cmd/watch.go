@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/quantumsheep/nyaa-cli/watch"
+	"github.com/urfave/cli/v2"
+)
+
+var WatchCmd = &cli.Command{
+	Name:  "watch",
+	Usage: "Periodically check the feeds and download the new releases matching the watch rules into --dir",
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "time between two checks, 0 checks only once",
+			Value: 15 * time.Minute,
+		},
+		&cli.DurationFlag{
+			Name:  "stall-timeout",
+			Usage: "time without progress after which a release is skipped until the next check, 0 waits forever (default: stall_timeout of the configuration, \"10m\")",
+		},
+		&cli.BoolFlag{
+			Name:  "mark-seen",
+			Usage: "mark the current matching releases as seen without downloading them, then exit",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		cfg := getConfig(c)
+		if c.IsSet("stall-timeout") {
+			cfg.StallTimeout = c.Duration("stall-timeout").String()
+		}
+
+		w, err := watch.NewWatcher(cfg)
+		if err != nil {
+			return err
+		}
+
+		if c.Bool("mark-seen") {
+			w.MarkOnly = true
+			return w.Check()
+		}
+
+		return w.Run(c.Duration("interval"))
+	},
+}
@@ -0,0 +1,405 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/dustin/go-humanize"
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+type Config struct {
+	// Directory used to store the .torrent files and downloads, the current
+	// directory when empty. The library only scans it when it's set.
+	Directory string `toml:"dir"`
+	// Directory used by the torrent engine while streaming, defaults to the
+	// user cache directory when empty
+	DataDirectory string `toml:"data_dir"`
+	// Maximum size of the data directory like "10GB", "0" disables the limit
+	CacheSize string `toml:"cache_size"`
+	// Time without progress after which a download is abandoned like "10m",
+	// "0" waits forever
+	StallTimeout string `toml:"stall_timeout"`
+
+	Player     string `toml:"player"`
+	Fullscreen bool   `toml:"fullscreen"`
+	Port       int    `toml:"port"`
+	// Directory searched for subtitles matching the played episode
+	SubtitlesDirectory string `toml:"subtitles_dir"`
+
+	Provider string `toml:"provider"`
+	// Category searched by default, the provider's default when empty
+	Category string `toml:"category"`
+	Filter   string `toml:"filter"`
+	SortBy   string `toml:"sort_by"`
+	OrderBy  string `toml:"order_by"`
+
+	// Columns of the results table, the least useful ones are hidden when the
+	// terminal is too narrow
+	Columns []string `toml:"columns"`
+
+	// Preset of key bindings: default, vim or emacs
+	Keymap string `toml:"keymap"`
+	// Keys bound to the actions on top of the preset, like
+	// "results.download" = ["d", "F10"]
+	Keys map[string][]string `toml:"keys"`
+
+	// Colors of the interface: dark, light, solarized or high-contrast
+	Theme string `toml:"theme"`
+
+	// Rules used by the watch command to pick the releases to download
+	Watch []WatchRule `toml:"watch"`
+
+	// Name of the profile used to send torrents to a remote client, optional
+	// when a single profile is configured
+	Client  string                   `toml:"client"`
+	Clients map[string]ClientProfile `toml:"clients"`
+
+	// Requests made to nyaa
+	HTTP HTTPConfig `toml:"http"`
+}
+
+type HTTPConfig struct {
+	// Maximum duration of a request like "30s"
+	Timeout string `toml:"timeout"`
+	// Number of times a failed request is sent again
+	Retries   int    `toml:"retries"`
+	UserAgent string `toml:"user_agent"`
+	// URL of an HTTP or SOCKS5 proxy like "socks5://localhost:1080"
+	Proxy string `toml:"proxy"`
+}
+
+type WatchRule struct {
+	Name        string `toml:"name"`
+	Query       string `toml:"query"`
+	Category    string `toml:"category"`
+	Submitter   string `toml:"submitter"`
+	TrustedOnly bool   `toml:"trusted_only"`
+	NameRegex   string `toml:"name_regex"`
+	Resolution  string `toml:"resolution"`
+}
+
+// ClientProfile describes a remote torrent client torrents can be sent to.
+type ClientProfile struct {
+	// One of qbittorrent, transmission or deluge
+	Type     string `toml:"type"`
+	URL      string `toml:"url"`
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+	// Directory the client downloads to, the client's default when empty
+	DownloadDirectory string `toml:"download_dir"`
+	// Category in qBittorrent or label in Transmission
+	Category string `toml:"category"`
+	// Send the magnet link instead of the link of the .torrent file
+	Magnet bool `toml:"magnet"`
+}
+
+// Printed instead of the passwords by Redacted
+const redactedSecret = "<redacted>"
+
+// Categories searched by default, by provider
+var defaultCategories = map[string]string{
+	"nyaa":    "anime-eng",
+	"sukebei": "all",
+}
+
+var clientTypes = []string{"qbittorrent", "transmission", "deluge"}
+
+// The submitter isn't in the search feeds, only in the page of each torrent
+var columnNames = []string{"id", "saved", "watched", "size", "date", "seeders", "leechers", "downloads", "trusted", "remake", "category", "resolution", "episode", "name"}
+
+var keymapNames = []string{"default", "vim", "emacs"}
+
+var themeNames = []string{"dark", "light", "solarized", "high-contrast"}
+
+func Default() *Config {
+	return &Config{
+		Directory:     "",
+		DataDirectory: "",
+		CacheSize:     "10GB",
+		StallTimeout:  "10m",
+		Player:        "vlc",
+		Fullscreen:    false,
+		Port:          3001,
+		Provider:      "nyaa",
+		Category:      "",
+		Filter:        "no-filter",
+		SortBy:        "date",
+		OrderBy:       "desc",
+		Columns:       []string{"id", "saved", "watched", "size", "date", "seeders", "leechers", "trusted", "resolution", "episode", "name"},
+		Keymap:        "default",
+		Theme:         "dark",
+		HTTP: HTTPConfig{
+			Timeout:   utils.DefaultHTTPOptions.Timeout.String(),
+			Retries:   utils.DefaultHTTPOptions.Retries,
+			UserAgent: utils.DefaultHTTPOptions.UserAgent,
+		},
+	}
+}
+
+func Path() (string, error) {
+	directory, err := utils.ConfigDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(directory, "config.toml"), nil
+}
+
+// Load reads the configuration file on top of the default values. A missing
+// file is not an error.
+func Load() (*Config, error) {
+	config := Default()
+
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = toml.DecodeFile(path, config)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func (c *Config) Validate() error {
+	if c.Provider != "nyaa" && c.Provider != "sukebei" {
+		return fmt.Errorf("unknown provider %q, available options: nyaa, sukebei", c.Provider)
+	}
+
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %d", c.Port)
+	}
+
+	if err := checkOption("player", c.Player, utils.VideoPlayers); err != nil {
+		return err
+	}
+
+	if err := checkOption("category", c.SearchCategory(), utils.Categories(c.Provider)); err != nil {
+		return err
+	}
+
+	if err := checkOption("filter", c.Filter, utils.Filters()); err != nil {
+		return err
+	}
+
+	if err := checkOption("sort_by", c.SortBy, utils.SortKeys()); err != nil {
+		return err
+	}
+
+	if err := checkOption("order_by", c.OrderBy, utils.Orders()); err != nil {
+		return err
+	}
+
+	for _, rule := range c.Watch {
+		if rule.Category == "" {
+			continue
+		}
+
+		if err := checkOption(fmt.Sprintf("category of watch rule %q", rule.Name), rule.Category, utils.Categories(c.Provider)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.CacheMaxSize(); err != nil {
+		return fmt.Errorf("invalid cache size %q: %w", c.CacheSize, err)
+	}
+
+	if _, err := c.StallTimeoutDuration(); err != nil {
+		return fmt.Errorf("invalid stall timeout %q: %w", c.StallTimeout, err)
+	}
+
+	for _, column := range c.Columns {
+		if !contains(columnNames, column) {
+			return fmt.Errorf("unknown column %q, available options: %s", column, strings.Join(columnNames, ", "))
+		}
+	}
+
+	if err := checkOption("keymap", c.Keymap, keymapNames); err != nil {
+		return err
+	}
+
+	if err := checkOption("theme", c.Theme, themeNames); err != nil {
+		return err
+	}
+
+	if c.Client != "" {
+		if _, ok := c.Clients[c.Client]; !ok {
+			return fmt.Errorf("unknown client profile %q", c.Client)
+		}
+	}
+
+	for name, profile := range c.Clients {
+		if !contains(clientTypes, profile.Type) {
+			return fmt.Errorf("client profile %q: unknown type %q, available options: %s", name, profile.Type, strings.Join(clientTypes, ", "))
+		}
+
+		if profile.URL == "" {
+			return fmt.Errorf("client profile %q: missing url", name)
+		}
+	}
+
+	if _, err := c.HTTPOptions(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// HTTPOptions returns the settings of the shared HTTP client.
+func (c *Config) HTTPOptions() (utils.HTTPOptions, error) {
+	options := utils.HTTPOptions{
+		Retries:   c.HTTP.Retries,
+		UserAgent: c.HTTP.UserAgent,
+		Proxy:     c.HTTP.Proxy,
+	}
+
+	timeout, err := time.ParseDuration(c.HTTP.Timeout)
+	if err != nil {
+		return options, fmt.Errorf("invalid http timeout %q: %w", c.HTTP.Timeout, err)
+	}
+	options.Timeout = timeout
+
+	if c.HTTP.Retries < 0 {
+		return options, fmt.Errorf("invalid http retries %d", c.HTTP.Retries)
+	}
+
+	if c.HTTP.Proxy != "" {
+		if _, err := utils.ParseProxy(c.HTTP.Proxy); err != nil {
+			return options, err
+		}
+	}
+
+	return options, nil
+}
+
+// ClientProfile returns the selected remote client profile, or the only one
+// configured.
+func (c *Config) ClientProfile() (*ClientProfile, error) {
+	if c.Client != "" {
+		profile, ok := c.Clients[c.Client]
+		if !ok {
+			return nil, fmt.Errorf("unknown client profile %q", c.Client)
+		}
+
+		return &profile, nil
+	}
+
+	switch len(c.Clients) {
+	case 0:
+		return nil, errors.New("no remote client configured")
+	case 1:
+		for _, profile := range c.Clients {
+			return &profile, nil
+		}
+	}
+
+	return nil, errors.New("several remote clients are configured, select one with --client")
+}
+
+func (c *Config) CacheMaxSize() (uint64, error) {
+	return humanize.ParseBytes(c.CacheSize)
+}
+
+func (c *Config) StallTimeoutDuration() (time.Duration, error) {
+	return time.ParseDuration(c.StallTimeout)
+}
+
+// SearchCategory returns the category searched by default.
+func (c *Config) SearchCategory() string {
+	if c.Category != "" {
+		return c.Category
+	}
+
+	return defaultCategories[c.Provider]
+}
+
+// OutputDirectory returns the directory the .torrent files and downloads are
+// stored in.
+func (c *Config) OutputDirectory() string {
+	if c.Directory != "" {
+		return c.Directory
+	}
+
+	return "."
+}
+
+// LibraryDirectory returns the directory indexed by the library, which must
+// be configured explicitly so the current directory is never scanned by
+// accident.
+func (c *Config) LibraryDirectory() (string, error) {
+	if c.Directory == "" {
+		return "", fmt.Errorf("no download directory is configured, set dir in the configuration file or use --dir")
+	}
+
+	return c.Directory, nil
+}
+
+// StreamingDirectory returns the data directory, or its default location in
+// the user cache directory.
+func (c *Config) StreamingDirectory() (string, error) {
+	if c.DataDirectory != "" {
+		return c.DataDirectory, nil
+	}
+
+	directory, err := utils.CacheDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(directory, "torrents"), nil
+}
+
+// Redacted returns a copy of the configuration without the client passwords
+// and proxy credentials, to be printed.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	redacted.Clients = make(map[string]ClientProfile, len(c.Clients))
+	for name, profile := range c.Clients {
+		if profile.Password != "" {
+			profile.Password = redactedSecret
+		}
+
+		redacted.Clients[name] = profile
+	}
+
+	if proxy, err := url.Parse(c.HTTP.Proxy); err == nil && proxy.User != nil {
+		redacted.HTTP.Proxy = proxy.Redacted()
+	}
+
+	return &redacted
+}
+
+func (c *Config) Write(w io.Writer) error {
+	return toml.NewEncoder(w).Encode(c)
+}
+
+// checkOption fails with an error naming the field when the value isn't one
+// of the options.
+func checkOption(field string, value string, options []string) error {
+	if !contains(options, value) {
+		return fmt.Errorf("invalid %s %q, available options: %s", field, value, strings.Join(options, ", "))
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/storage"
+)
+
+// NewCacheEngine creates an engine keeping the data of every torrent in a
+// directory named after its infohash. The completed pieces are recorded in a
+// bolt database so the data of previous runs is reused without being
+// downloaded or verified again.
+func NewCacheEngine(cacheDirectory string) (*Engine, error) {
+	completion, err := storage.NewBoltPieceCompletion(cacheDirectory)
+	if err != nil {
+		// The database is locked when another instance is running
+		return nil, fmt.Errorf("piece completion database in %q (is another nyaa-cli streaming?): %w", cacheDirectory, err)
+	}
+
+	engine, err := newEngine(cacheDirectory, storage.NewFileOpts(storage.NewFileClientOpts{
+		ClientBaseDir: cacheDirectory,
+		TorrentDirMaker: func(baseDir string, info *metainfo.Info, infoHash metainfo.Hash) string {
+			return filepath.Join(baseDir, infoHash.HexString())
+		},
+		PieceCompletion: completion,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	engine.KeyByInfoHash = true
+	return engine, nil
+}
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -15,6 +17,7 @@ import (
 	_ "unsafe"
 
 	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/storage"
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/quantumsheep/nyaa-cli/utils"
@@ -23,8 +26,17 @@ import (
 
 var httpRegex = regexp.MustCompile(`^https?:\/\/`)
 
+// ErrStalled is returned when a download makes no progress for the stall
+// timeout of the engine.
+var ErrStalled = errors.New("download stalled")
+
 type Engine struct {
 	DataDirectory string
+	// Each torrent is stored in a directory named after its infohash
+	KeyByInfoHash bool
+	// Time without progress after which a download is abandoned, zero waits
+	// forever
+	StallTimeout time.Duration
 
 	client  *torrent.Client
 	torrent *torrent.Torrent
@@ -35,10 +47,15 @@ type Engine struct {
 }
 
 func NewEngine(dataDirectory string) (*Engine, error) {
+	return newEngine(dataDirectory, nil)
+}
+
+func newEngine(dataDirectory string, clientStorage storage.ClientImpl) (*Engine, error) {
 	var err error
 
 	torrentConfig := torrent.NewDefaultClientConfig()
 	torrentConfig.DataDir = dataDirectory
+	torrentConfig.DefaultStorage = clientStorage
 	torrentConfig.NoUpload = true
 	torrentConfig.DisableTCP = false
 	torrentConfig.ListenPort = 0
@@ -213,7 +230,6 @@ func (e *Engine) RunServer(port string, index int) error {
 }
 
 func (e *Engine) StopServer() error {
-	e.DropCurrentTorrent()
 	return e.server.Close()
 }
 
@@ -302,6 +318,99 @@ func (e *Engine) SetTorrentFromPath(torrentPath string) error {
 	return nil
 }
 
+// DownloadAll downloads the whole current torrent into the data directory and
+// blocks until every piece is complete, the context is done or the download
+// stalls.
+func (e *Engine) DownloadAll(ctx context.Context) error {
+	if err := e.waitInfo(ctx); err != nil {
+		return err
+	}
+
+	e.torrent.DownloadAll()
+
+	return e.waitDownload(ctx, e.torrent.BytesMissing)
+}
+
+// waitInfo waits for the metainfo of the current torrent, for at most the
+// stall timeout.
+func (e *Engine) waitInfo(ctx context.Context) error {
+	var timeout <-chan time.Time
+	if e.StallTimeout > 0 {
+		timer := time.NewTimer(e.StallTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-e.torrent.GotInfo():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return fmt.Errorf("%s: no metainfo: %w", e.torrent.Name(), ErrStalled)
+	}
+}
+
+// waitDownload polls the bytes left to download until there are none, failing
+// when they don't decrease for the stall timeout.
+func (e *Engine) waitDownload(ctx context.Context, bytesLeft func() int64) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	left := bytesLeft()
+	progressedAt := time.Now()
+
+	for left > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if current := bytesLeft(); current < left {
+			left = current
+			progressedAt = time.Now()
+		} else if e.StallTimeout > 0 && time.Since(progressedAt) > e.StallTimeout {
+			return fmt.Errorf("%s: %w", e.torrent.Name(), ErrStalled)
+		}
+	}
+
+	return nil
+}
+
+// DownloadFiles downloads the given files of the current torrent into the
+// data directory and blocks until they are complete. The other files are not
+// downloaded, except for the pieces they share with the given files.
+func (e *Engine) DownloadFiles(ctx context.Context, indices []int) error {
+	if err := e.waitInfo(ctx); err != nil {
+		return err
+	}
+
+	files := e.torrent.Files()
+	for _, i := range indices {
+		if i < 0 || i >= len(files) {
+			return fmt.Errorf("%s has no file %d", e.torrent.Name(), i)
+		}
+	}
+
+	for _, file := range files {
+		file.SetPriority(torrent.PiecePriorityNone)
+	}
+
+	for _, i := range indices {
+		files[i].Download()
+	}
+
+	return e.waitDownload(ctx, func() int64 {
+		var left int64
+		for _, i := range indices {
+			left += files[i].Length() - files[i].BytesCompleted()
+		}
+
+		return left
+	})
+}
+
 func (e *Engine) DropCurrentTorrent() {
 	e.torrent.Drop()
 }
@@ -314,3 +423,111 @@ func (e *Engine) GetFileName(i int) string {
 	files := e.torrent.Files()
 	return files[i].DisplayPath()
 }
+
+// Length returns the size of a file of the current torrent, or of the whole
+// torrent when i is -1.
+func (e *Engine) Length(i int) int64 {
+	<-e.torrent.GotInfo()
+
+	if i == -1 {
+		return e.torrent.Length()
+	}
+
+	return e.torrent.Files()[i].Length()
+}
+
+// FileIndex returns the index of the file of the current torrent at the given
+// path, which starts with the torrent name or not.
+func (e *Engine) FileIndex(path string) (int, error) {
+	<-e.torrent.GotInfo()
+
+	for i, file := range e.torrent.Files() {
+		if file.Path() == path || file.DisplayPath() == path {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("%s has no file %s", e.torrent.Name(), path)
+}
+
+// FilePaths returns the path of every file of the current torrent, in the
+// order of their indices.
+func (e *Engine) FilePaths() []string {
+	files := e.torrent.Files()
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path()
+	}
+
+	return paths
+}
+
+// ExtractFile downloads a file of the current torrent ahead of the others and
+// copies it into the directory, at its path in the torrent, once complete.
+func (e *Engine) ExtractFile(ctx context.Context, index int, directory string) (string, error) {
+	file := e.torrent.Files()[index]
+	file.SetPriority(torrent.PiecePriorityNow)
+
+	// Files of different folders can have the same name, their path in the
+	// torrent is kept
+	segments := strings.Split(file.DisplayPath(), "/")
+	for i, segment := range segments {
+		segments[i] = utils.SafeFilename(segment, "")
+	}
+	destination := filepath.Join(append([]string{directory}, segments...)...)
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return "", err
+	}
+
+	// Renamed once complete so a failed copy leaves no partial file
+	f, err := os.CreateTemp(filepath.Dir(destination), ".nyaa-cli-*")
+	if err != nil {
+		return "", err
+	}
+
+	reader := file.NewReader()
+	defer reader.Close()
+
+	_, err = io.Copy(f, contextReader{ctx, reader})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), destination)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return destination, nil
+}
+
+type contextReader struct {
+	ctx    context.Context
+	reader torrent.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	return r.reader.ReadContext(r.ctx, p)
+}
+
+func (e *Engine) InfoHash() string {
+	return e.torrent.InfoHash().HexString()
+}
+
+// LocalPath returns the location of a file of the current torrent on disk.
+func (e *Engine) LocalPath(index int) string {
+	directory := e.DataDirectory
+	if e.KeyByInfoHash {
+		directory = filepath.Join(directory, e.InfoHash())
+	}
+
+	return filepath.Join(directory, filepath.FromSlash(e.torrent.Files()[index].Path()))
+}
+
+func (e *Engine) IsComplete() bool {
+	return e.torrent.Info() != nil && e.torrent.BytesMissing() == 0
+}
@@ -3,21 +3,25 @@ module github.com/quantumsheep/nyaa-cli
 go 1.18
 
 require (
+	github.com/BurntSushi/toml v1.2.1
+	github.com/Microsoft/go-winio v0.5.2
+	github.com/PuerkitoBio/goquery v1.5.1
 	github.com/anacrolix/torrent v1.43.1
 	github.com/dustin/go-humanize v1.0.0
 	github.com/fatih/color v1.13.0
 	github.com/gdamore/tcell/v2 v2.5.1
 	github.com/gocolly/colly v1.2.0
+	github.com/mmcdole/gofeed v1.1.3
 	github.com/quantumsheep/go-nyaa/v2 v2.1.0
 	github.com/quantumsheep/range-parser v1.1.0
 	github.com/rivo/tview v0.0.0-20220307222120-9994674d60a8
 	github.com/urfave/cli/v2 v2.6.0
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/sys v0.0.0-20220513210249-45d2b4557a2a
 )
 
 require (
 	crawshaw.io/sqlite v0.3.3-0.20210127221821-98b1f83c5508 // indirect
-	github.com/PuerkitoBio/goquery v1.5.1 // indirect
 	github.com/RoaringBitmap/roaring v1.0.1-0.20220510143707-3f418c4f42a4 // indirect
 	github.com/ajwerner/btree v0.0.0-20211221152037-f427b3e689c0 // indirect
 	github.com/anacrolix/chansync v0.3.0 // indirect
@@ -61,7 +65,6 @@ require (
 	github.com/mattn/go-colorable v0.1.9 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
 	github.com/mattn/go-runewidth v0.0.13 // indirect
-	github.com/mmcdole/gofeed v1.1.3 // indirect
 	github.com/mmcdole/goxpp v0.0.0-20181012175147-0068e33feabf // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.1 // indirect
@@ -90,7 +93,6 @@ require (
 	github.com/saintfish/chardet v0.0.0-20120816061221-3af4cd4741ca // indirect
 	github.com/temoto/robotstxt v1.1.2 // indirect
 	github.com/tidwall/btree v0.7.2-0.20211211132910-4215444137fc // indirect
-	go.etcd.io/bbolt v1.3.6 // indirect
 	golang.org/x/crypto v0.0.0-20220131195533-30dcbda58838 // indirect
 	golang.org/x/exp v0.0.0-20220428152302-39d4317da171 // indirect
 	golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd // indirect
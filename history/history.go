@@ -0,0 +1,119 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+const maxQueries = 50
+
+type SavedSearch struct {
+	Name     string `json:"name"`
+	Query    string `json:"query"`
+	Category string `json:"category"`
+	Filter   string `json:"filter"`
+	SortBy   string `json:"sort_by"`
+	OrderBy  string `json:"order_by"`
+}
+
+type History struct {
+	Queries []string      `json:"queries"`
+	Saved   []SavedSearch `json:"saved"`
+
+	path string
+}
+
+func Load() (*History, error) {
+	directory, err := utils.ConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{
+		path: filepath.Join(directory, "searches.json"),
+	}
+
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+func (h *History) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(h.path, data, 0644)
+}
+
+// AddQuery moves the query to the top of the history, dropping the oldest
+// entries once the history is full.
+func (h *History) AddQuery(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+
+	h.RemoveQuery(query)
+	h.Queries = append([]string{query}, h.Queries...)
+
+	if len(h.Queries) > maxQueries {
+		h.Queries = h.Queries[:maxQueries]
+	}
+}
+
+func (h *History) RemoveQuery(query string) {
+	for i, q := range h.Queries {
+		if q == query {
+			h.Queries = append(h.Queries[:i], h.Queries[i+1:]...)
+			return
+		}
+	}
+}
+
+// SaveSearch adds the search or replaces the one with the same name.
+func (h *History) SaveSearch(search SavedSearch) {
+	for i, s := range h.Saved {
+		if s.Name == search.Name {
+			h.Saved[i] = search
+			return
+		}
+	}
+
+	h.Saved = append(h.Saved, search)
+}
+
+func (h *History) FindSaved(name string) (SavedSearch, bool) {
+	for _, s := range h.Saved {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return SavedSearch{}, false
+}
+
+func (h *History) RemoveSaved(name string) {
+	for i, s := range h.Saved {
+		if s.Name == name {
+			h.Saved = append(h.Saved[:i], h.Saved[i+1:]...)
+			return
+		}
+	}
+}
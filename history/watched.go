@@ -0,0 +1,121 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+const maxWatched = 200
+
+type WatchEntry struct {
+	GUID string `json:"guid"`
+	Link string `json:"link"`
+	// FileIndex is -1 when the torrent has a single file
+	FileIndex int `json:"file_index"`
+	// Path of the file in the torrent, empty for single file torrents
+	FilePath string `json:"file_path,omitempty"`
+	Name     string `json:"name"`
+
+	WatchedAt time.Time `json:"watched_at"`
+	// Position and Duration are in seconds, they stay at 0 when the player
+	// can't report them
+	Position float64 `json:"position"`
+	Duration float64 `json:"duration"`
+}
+
+type Watched struct {
+	Entries []WatchEntry `json:"entries"`
+
+	path string
+}
+
+func LoadWatched() (*Watched, error) {
+	directory, err := utils.ConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watched{
+		path: filepath.Join(directory, "watched.json"),
+	}
+
+	data, err := os.ReadFile(w.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, w); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *Watched) Save() error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(w.path, data, 0644)
+}
+
+// Record moves the entry to the top of the history, replacing the previous
+// entry of the same file.
+func (w *Watched) Record(entry WatchEntry) {
+	w.Remove(entry.GUID, entry.FileIndex)
+	w.Entries = append([]WatchEntry{entry}, w.Entries...)
+
+	if len(w.Entries) > maxWatched {
+		w.Entries = w.Entries[:maxWatched]
+	}
+}
+
+func (w *Watched) Remove(guid string, fileIndex int) {
+	for i, e := range w.Entries {
+		if e.GUID == guid && e.FileIndex == fileIndex {
+			w.Entries = append(w.Entries[:i], w.Entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (w *Watched) Find(guid string, fileIndex int) (WatchEntry, bool) {
+	for _, e := range w.Entries {
+		if e.GUID == guid && e.FileIndex == fileIndex {
+			return e, true
+		}
+	}
+
+	return WatchEntry{}, false
+}
+
+// FindFile returns the entry of the file of the torrent at the given path.
+func (w *Watched) FindFile(guid string, path string) (WatchEntry, bool) {
+	for _, e := range w.Entries {
+		if e.GUID == guid && e.FilePath == path {
+			return e, true
+		}
+	}
+
+	return WatchEntry{}, false
+}
+
+// HasWatched tells if any file of the torrent has been played.
+func (w *Watched) HasWatched(guid string) bool {
+	for _, e := range w.Entries {
+		if e.GUID == guid {
+			return true
+		}
+	}
+
+	return false
+}
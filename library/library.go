@@ -0,0 +1,245 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quantumsheep/nyaa-cli/parser"
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+var videoExtensions = map[string]bool{
+	".mkv":  true,
+	".mp4":  true,
+	".avi":  true,
+	".webm": true,
+	".m2ts": true,
+	".mov":  true,
+}
+
+type Item struct {
+	Path    string `json:"path"`
+	Series  string `json:"series"`
+	Season  int    `json:"season"`
+	Episode int    `json:"episode"`
+	Size    int64  `json:"size"`
+
+	// Source of the file, empty when it wasn't downloaded by nyaa-cli
+	GUID     string `json:"guid,omitempty"`
+	InfoHash string `json:"info_hash,omitempty"`
+	// Fully downloaded while streaming, the file is in the streaming cache
+	// and goes away when the cache evicts it
+	Streamed bool `json:"streamed,omitempty"`
+
+	AddedAt time.Time `json:"added_at"`
+}
+
+type Library struct {
+	Items []Item `json:"items"`
+
+	path string
+}
+
+func Load() (*Library, error) {
+	directory, err := utils.ConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Library{
+		path: filepath.Join(directory, "library.json"),
+	}
+
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (l *Library) Save() error {
+	sort.SliceStable(l.Items, func(i, j int) bool {
+		a, b := l.Items[i], l.Items[j]
+
+		if !strings.EqualFold(a.Series, b.Series) {
+			return strings.ToLower(a.Series) < strings.ToLower(b.Series)
+		}
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		if a.Episode != b.Episode {
+			return a.Episode < b.Episode
+		}
+
+		return a.Path < b.Path
+	})
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(l.path, data, 0644)
+}
+
+func NewItem(path string, size int64) Item {
+	release := parser.Parse(filepath.Base(path))
+
+	series := release.Title
+	if series == "" {
+		series = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return Item{
+		Path:    path,
+		Series:  series,
+		Season:  release.Season,
+		Episode: release.Episode,
+		Size:    size,
+		AddedAt: time.Now(),
+	}
+}
+
+// Add indexes the item, keeping the source of an already indexed file when the
+// new item doesn't know it.
+func (l *Library) Add(item Item) {
+	for i, existing := range l.Items {
+		if existing.Path != item.Path {
+			continue
+		}
+
+		if item.GUID == "" {
+			item.GUID = existing.GUID
+			item.InfoHash = existing.InfoHash
+			item.Streamed = existing.Streamed
+		}
+		item.AddedAt = existing.AddedAt
+
+		l.Items[i] = item
+		return
+	}
+
+	l.Items = append(l.Items, item)
+}
+
+// Scan indexes the video files of the directory and forgets the items whose
+// file doesn't exist anymore.
+func (l *Library) Scan(directory string) error {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return err
+	}
+
+	l.forgetMissing()
+
+	err = filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && IsVideo(path) {
+			l.Add(NewItem(path, info.Size()))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return l.Save()
+}
+
+// Prune forgets the items whose file doesn't exist anymore.
+func (l *Library) Prune() error {
+	l.forgetMissing()
+
+	return l.Save()
+}
+
+func (l *Library) forgetMissing() {
+	items := l.Items[:0]
+	for _, item := range l.Items {
+		if _, err := os.Stat(item.Path); err == nil {
+			items = append(items, item)
+		}
+	}
+	l.Items = items
+}
+
+// Remove deletes the file of the item and forgets it.
+func (l *Library) Remove(index int) error {
+	if index < 0 || index >= len(l.Items) {
+		return fmt.Errorf("no library item #%d", index+1)
+	}
+
+	if err := os.Remove(l.Items[index].Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	l.Items = append(l.Items[:index], l.Items[index+1:]...)
+	return l.Save()
+}
+
+// Label is a short human readable name like "Show S01E05".
+func (item Item) Label() string {
+	label := item.Series
+
+	if item.Season > 0 {
+		label += fmt.Sprintf(" S%02d", item.Season)
+		if item.Episode > 0 {
+			label += fmt.Sprintf("E%02d", item.Episode)
+		}
+	} else if item.Episode > 0 {
+		label += fmt.Sprintf(" - %02d", item.Episode)
+	}
+
+	return label
+}
+
+func IsVideo(path string) bool {
+	return videoExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Pins tells if a streamed file of the library lives in the torrent's
+// directory of the streaming cache, in which case the cache keeps the torrent.
+func (l *Library) Pins(cacheDirectory string, infoHash string) bool {
+	directory, err := filepath.Abs(filepath.Join(cacheDirectory, infoHash))
+	if err != nil {
+		return false
+	}
+
+	for _, item := range l.Items {
+		if item.InfoHash == infoHash && item.Streamed && isInDirectory(item.Path, directory) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isInDirectory(path string, directory string) bool {
+	relative, err := filepath.Rel(directory, path)
+	if err != nil {
+		return false
+	}
+
+	return relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
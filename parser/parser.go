@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Release holds the information found in a typical fansub release name like
+// "[Group] Show - 05 (1080p) [ABCD1234].mkv".
+type Release struct {
+	Group string
+	Title string
+
+	// Season is 0 when the name doesn't mention one
+	Season int
+	// Episode is 0 when the name doesn't mention one, EpisodeEnd is only set
+	// for batches covering a range of episodes
+	Episode    int
+	EpisodeEnd int
+	Batch      bool
+
+	Resolution string
+	Codec      string
+	CRC        string
+}
+
+var (
+	videoExtensions = map[string]bool{
+		".mkv":  true,
+		".mp4":  true,
+		".avi":  true,
+		".webm": true,
+		".m2ts": true,
+	}
+
+	tagRegex        = regexp.MustCompile(`\[([^\]]*)\]|\(([^)]*)\)|【([^】]*)】`)
+	leadingTagRegex = regexp.MustCompile(`^\s*(?:\[([^\]]+)\]|【([^】]+)】)`)
+	crcRegex        = regexp.MustCompile(`^[0-9A-Fa-f]{8}$`)
+	resolutionRegex = regexp.MustCompile(`(?i)\b(\d{3,4})[pi]\b|\b\d{3,4}x(\d{3,4})\b|\b(4k|uhd)\b`)
+	codecRegex      = regexp.MustCompile(`(?i)\b(hevc|avc|av1|xvid|[xh]\.?26[45])\b`)
+	rangeRegex      = regexp.MustCompile(`^\s*(?:e|ep)?(\d{1,4})\s*[-~]\s*(?:e|ep)?(\d{1,4})\s*$`)
+	batchRegex      = regexp.MustCompile(`(?i)\b(batch|complete)\b`)
+	sceneGroupRegex = regexp.MustCompile(`-(\w+)$`)
+
+	seasonEpisodeRegex = regexp.MustCompile(`(?i)\bS(\d{1,2})\s*E(\d{1,4})(?:\s*-\s*E?(\d{1,4}))?\b`)
+	dashEpisodeRegex   = regexp.MustCompile(`\s-\s*(\d{1,4})(?:v\d)?(?:\s*[-~]\s*(\d{1,4})(?:v\d)?)?(?:\s|$)`)
+	seasonRegex        = regexp.MustCompile(`(?i)\b(?:S(\d{1,2})|Season\s*(\d{1,2})|(\d{1,2})(?:st|nd|rd|th)\s+Season)\b`)
+)
+
+func Parse(name string) Release {
+	var release Release
+
+	name = strings.TrimSpace(name)
+	if videoExtensions[strings.ToLower(path.Ext(name))] {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+
+	if match := leadingTagRegex.FindStringSubmatch(name); match != nil {
+		release.Group = strings.TrimSpace(match[1] + match[2])
+		name = name[len(match[0]):]
+	}
+
+	for _, match := range tagRegex.FindAllStringSubmatch(name, -1) {
+		tag := strings.TrimSpace(match[1] + match[2] + match[3])
+
+		if crcRegex.MatchString(tag) && !isNumber(tag) {
+			release.CRC = strings.ToUpper(tag)
+		} else if m := rangeRegex.FindStringSubmatch(tag); m != nil {
+			release.Episode, _ = strconv.Atoi(m[1])
+			release.EpisodeEnd, _ = strconv.Atoi(m[2])
+			release.Batch = true
+		}
+	}
+
+	release.Resolution = findResolution(name)
+	if codec := codecRegex.FindString(name); codec != "" {
+		release.Codec = normalizeCodec(codec)
+	}
+
+	if batchRegex.MatchString(name) {
+		release.Batch = true
+	}
+
+	core := strings.TrimSpace(tagRegex.ReplaceAllString(name, " "))
+	if !strings.Contains(core, " ") {
+		// Scene style names like "Show.S01E05.1080p.WEB.x264-GROUP"
+		if release.Group == "" {
+			if m := sceneGroupRegex.FindStringSubmatchIndex(core); m != nil {
+				release.Group = core[m[2]:m[3]]
+				core = core[:m[0]]
+			}
+		}
+
+		core = strings.NewReplacer(".", " ", "_", " ").Replace(core)
+	}
+
+	title := core
+
+	if m := seasonEpisodeRegex.FindStringSubmatchIndex(core); m != nil {
+		release.Season = atoi(core, m[2], m[3])
+		release.Episode = atoi(core, m[4], m[5])
+		if m[6] != -1 {
+			release.EpisodeEnd = atoi(core, m[6], m[7])
+			release.Batch = true
+		}
+
+		title = core[:m[0]]
+	} else if m := dashEpisodeRegex.FindStringSubmatchIndex(core); m != nil {
+		release.Episode = atoi(core, m[2], m[3])
+		if m[4] != -1 {
+			release.EpisodeEnd = atoi(core, m[4], m[5])
+			release.Batch = true
+		}
+
+		title = core[:m[0]]
+	} else {
+		// Without an episode marker, the title ends at the first technical tag
+		if m := resolutionRegex.FindStringIndex(title); m != nil {
+			title = title[:m[0]]
+		}
+	}
+
+	if m := seasonRegex.FindStringSubmatchIndex(title); m != nil {
+		for i := 2; i < len(m); i += 2 {
+			if m[i] != -1 {
+				release.Season = atoi(title, m[i], m[i+1])
+			}
+		}
+
+		title = title[:m[0]] + title[m[1]:]
+	} else if release.Season == 0 {
+		// The season may be written in a tag, like "(Season 2)"
+		if m := seasonRegex.FindStringSubmatch(name); m != nil {
+			release.Season, _ = strconv.Atoi(m[1] + m[2] + m[3])
+		}
+	}
+
+	release.Title = strings.Trim(strings.Join(strings.Fields(title), " "), " -_.")
+
+	return release
+}
+
+// EpisodeLabel is a short human readable form of the season and episode, like
+// "S02E05", "05" or "01-12".
+func (r Release) EpisodeLabel() string {
+	label := ""
+
+	if r.Season > 0 {
+		label = fmt.Sprintf("S%02d", r.Season)
+		if r.Episode > 0 {
+			label += "E"
+		}
+	}
+
+	if r.Episode > 0 {
+		label += fmt.Sprintf("%02d", r.Episode)
+	}
+
+	if r.EpisodeEnd > 0 {
+		label += fmt.Sprintf("-%02d", r.EpisodeEnd)
+	} else if r.Batch && r.Episode == 0 {
+		label = strings.TrimSpace(label + " Batch")
+	}
+
+	return label
+}
+
+func findResolution(name string) string {
+	m := resolutionRegex.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+
+	switch {
+	case m[1] != "":
+		return m[1] + "p"
+	case m[2] != "":
+		return m[2] + "p"
+	default:
+		return "2160p"
+	}
+}
+
+func normalizeCodec(codec string) string {
+	switch strings.ToLower(strings.ReplaceAll(codec, ".", "")) {
+	case "hevc", "x265", "h265":
+		return "HEVC"
+	case "avc", "x264", "h264":
+		return "AVC"
+	case "av1":
+		return "AV1"
+	default:
+		return strings.ToUpper(codec)
+	}
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.Atoi(value)
+	return err == nil
+}
+
+func atoi(s string, start int, end int) int {
+	value, _ := strconv.Atoi(s[start:end])
+	return value
+}
@@ -0,0 +1,87 @@
+package parser
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Release
+	}{
+		{
+			name: "[SubsPlease] Sousou no Frieren - 05 (1080p) [ABCD1234].mkv",
+			want: Release{Group: "SubsPlease", Title: "Sousou no Frieren", Episode: 5, Resolution: "1080p", CRC: "ABCD1234"},
+		},
+		{
+			name: "[Erai-raws] Kusuriya no Hitorigoto - 12 [720p][HEVC][Multiple Subtitle]",
+			want: Release{Group: "Erai-raws", Title: "Kusuriya no Hitorigoto", Episode: 12, Resolution: "720p", Codec: "HEVC"},
+		},
+		{
+			name: "【MMSUB】 Oshi no Ko - 03 [480p]",
+			want: Release{Group: "MMSUB", Title: "Oshi no Ko", Episode: 3, Resolution: "480p"},
+		},
+		{
+			name: "[Group] Show S2 - 07v2 (1080p)",
+			want: Release{Group: "Group", Title: "Show", Season: 2, Episode: 7, Resolution: "1080p"},
+		},
+		{
+			name: "[Group] Show - 03v3 [1920x1080 x264]",
+			want: Release{Group: "Group", Title: "Show", Episode: 3, Resolution: "1080p", Codec: "AVC"},
+		},
+		{
+			name: "Show.S01E05.1080p.WEB.x264-GROUP.mkv",
+			want: Release{Group: "GROUP", Title: "Show", Season: 1, Episode: 5, Resolution: "1080p", Codec: "AVC"},
+		},
+		{
+			name: "[Group] Show - 01-12 [1080p] [Batch]",
+			want: Release{Group: "Group", Title: "Show", Episode: 1, EpisodeEnd: 12, Batch: true, Resolution: "1080p"},
+		},
+		{
+			name: "[Group] Show (01-24) [BD 1080p]",
+			want: Release{Group: "Group", Title: "Show", Episode: 1, EpisodeEnd: 24, Batch: true, Resolution: "1080p"},
+		},
+		{
+			name: "[Group] Show S01E01-E13 [1080p]",
+			want: Release{Group: "Group", Title: "Show", Season: 1, Episode: 1, EpisodeEnd: 13, Batch: true, Resolution: "1080p"},
+		},
+		{
+			name: "[Group] Show 2nd Season [Complete] [4K]",
+			want: Release{Group: "Group", Title: "Show", Season: 2, Batch: true, Resolution: "2160p"},
+		},
+		{
+			name: "[Group] Show The Movie [1080p]",
+			want: Release{Group: "Group", Title: "Show The Movie", Resolution: "1080p"},
+		},
+		{
+			name: "Show Artbook",
+			want: Release{Title: "Show Artbook"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Parse(test.name); got != test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEpisodeLabel(t *testing.T) {
+	tests := []struct {
+		release Release
+		want    string
+	}{
+		{Release{Episode: 5}, "05"},
+		{Release{Season: 2, Episode: 5}, "S02E05"},
+		{Release{Episode: 1, EpisodeEnd: 12, Batch: true}, "01-12"},
+		{Release{Season: 2, Batch: true}, "S02 Batch"},
+		{Release{Batch: true}, "Batch"},
+		{Release{}, ""},
+	}
+
+	for _, test := range tests {
+		if got := test.release.EpisodeLabel(); got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.release, got, test.want)
+		}
+	}
+}
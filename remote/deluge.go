@@ -0,0 +1,107 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+// deluge talks to the JSON-RPC API of the Deluge Web UI. The Web UI has no
+// username, only a password.
+type deluge struct {
+	profile *config.ClientProfile
+	url     string
+	http    *http.Client
+	id      int
+}
+
+type delugeResponse struct {
+	Result interface{} `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (d *deluge) Add(link string) error {
+	loggedIn, err := d.call("auth.login", d.profile.Password)
+	if err != nil {
+		return err
+	}
+	if loggedIn != true {
+		return errors.New("deluge: invalid password")
+	}
+
+	if err := d.connect(); err != nil {
+		return err
+	}
+
+	options := map[string]interface{}{}
+	if d.profile.DownloadDirectory != "" {
+		options["download_location"] = d.profile.DownloadDirectory
+	}
+
+	method := "core.add_torrent_url"
+	if strings.HasPrefix(link, "magnet:") {
+		method = "core.add_torrent_magnet"
+	}
+
+	_, err = d.call(method, link, options)
+	return err
+}
+
+// connect connects the Web UI to the first daemon when it isn't connected yet.
+func (d *deluge) connect() error {
+	connected, err := d.call("web.connected")
+	if err != nil {
+		return err
+	}
+	if connected == true {
+		return nil
+	}
+
+	hosts, err := d.call("web.get_hosts")
+	if err != nil {
+		return err
+	}
+
+	list, ok := hosts.([]interface{})
+	if !ok || len(list) == 0 {
+		return errors.New("deluge: no daemon configured in the web ui")
+	}
+
+	host, ok := list[0].([]interface{})
+	if !ok || len(host) == 0 {
+		return errors.New("deluge: unexpected host list")
+	}
+
+	_, err = d.call("web.connect", host[0])
+	return err
+}
+
+func (d *deluge) call(method string, params ...interface{}) (interface{}, error) {
+	d.id++
+
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	body := map[string]interface{}{
+		"method": method,
+		"params": params,
+		"id":     d.id,
+	}
+
+	var result delugeResponse
+	if _, err := postJSON(d.http, d.url+"/json", nil, body, &result); err != nil {
+		return nil, fmt.Errorf("deluge: %w", err)
+	}
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("deluge %s: %s", method, result.Error.Message)
+	}
+
+	return result.Result, nil
+}
@@ -0,0 +1,140 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+type delugeCall struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// fakeDeluge answers like the JSON-RPC API of the Deluge Web UI, with a
+// daemon to connect to. addError makes adding torrents fail.
+func fakeDeluge(t *testing.T, calls *[]delugeCall, addError string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var call delugeCall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*calls = append(*calls, call)
+
+		response := map[string]interface{}{"result": nil, "error": nil}
+
+		switch call.Method {
+		case "auth.login":
+			response["result"] = call.Params[0] == "deluge"
+		case "web.connected":
+			response["result"] = false
+		case "web.get_hosts":
+			response["result"] = [][]interface{}{{"daemon", "127.0.0.1", 58846, "Offline"}}
+		case "core.add_torrent_url", "core.add_torrent_magnet":
+			if addError != "" {
+				response["error"] = map[string]interface{}{"message": addError, "code": 4}
+			} else {
+				response["result"] = "0123456789abcdef0123456789abcdef01234567"
+			}
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDelugeAdd(t *testing.T) {
+	var calls []delugeCall
+	server := fakeDeluge(t, &calls, "")
+
+	client, err := New(&config.ClientProfile{Type: "deluge", URL: server.URL, Password: "deluge", DownloadDirectory: "/data/anime"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Add("magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567"); err != nil {
+		t.Fatal(err)
+	}
+
+	var methods []string
+	for _, call := range calls {
+		methods = append(methods, call.Method)
+	}
+
+	want := []string{"auth.login", "web.connected", "web.get_hosts", "web.connect", "core.add_torrent_magnet"}
+	if len(methods) != len(want) {
+		t.Fatalf("got calls %v, want %v", methods, want)
+	}
+	for i := range want {
+		if methods[i] != want[i] {
+			t.Fatalf("got calls %v, want %v", methods, want)
+		}
+	}
+
+	if host := calls[3].Params[0]; host != "daemon" {
+		t.Errorf("connected to %v, want daemon", host)
+	}
+
+	options, _ := calls[4].Params[1].(map[string]interface{})
+	if options["download_location"] != "/data/anime" {
+		t.Errorf("got options %v, want the download location", options)
+	}
+}
+
+func TestDelugeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		addError string
+		want     string
+	}{
+		{name: "wrong password", password: "wrong", want: "deluge: invalid password"},
+		{name: "rpc error", password: "deluge", addError: "Torrent already in session", want: "deluge core.add_torrent_url: Torrent already in session"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls []delugeCall
+			server := fakeDeluge(t, &calls, test.addError)
+
+			client, err := New(&config.ClientProfile{Type: "deluge", URL: server.URL, Password: test.password})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := client.Add("https://nyaa.si/download/1.torrent"); err == nil || err.Error() != test.want {
+				t.Errorf("got error %v, want %s", err, test.want)
+			}
+		})
+	}
+}
+
+func TestDelugeErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := New(&config.ClientProfile{Type: "deluge", URL: server.URL, Password: "deluge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "deluge: " + server.URL + "/json: 502 Bad Gateway"
+	if err := client.Add("magnet:?xt=urn:btih:0"); err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
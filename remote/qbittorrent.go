@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+// qbittorrent talks to the qBittorrent WebUI API.
+type qbittorrent struct {
+	profile *config.ClientProfile
+	url     string
+	http    *http.Client
+}
+
+func (q *qbittorrent) Add(link string) error {
+	if err := q.post("/api/v2/auth/login", url.Values{
+		"username": {q.profile.Username},
+		"password": {q.profile.Password},
+	}); err != nil {
+		return fmt.Errorf("qbittorrent login: %w", err)
+	}
+
+	form := url.Values{"urls": {link}}
+	if q.profile.DownloadDirectory != "" {
+		form.Set("savepath", q.profile.DownloadDirectory)
+	}
+	if q.profile.Category != "" {
+		form.Set("category", q.profile.Category)
+	}
+
+	if err := q.post("/api/v2/torrents/add", form); err != nil {
+		return fmt.Errorf("qbittorrent: %w", err)
+	}
+
+	return nil
+}
+
+// post sends the form and checks the "Ok." answer of the API.
+func (q *qbittorrent) post(path string, form url.Values) error {
+	req, err := http.NewRequest(http.MethodPost, q.url+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// Required by the CSRF protection of the WebUI
+	req.Header.Set("Referer", q.url)
+
+	res, err := q.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", path, res.Status)
+	}
+
+	if answer := strings.TrimSpace(string(body)); answer != "Ok." {
+		return fmt.Errorf("%s: %s", path, answer)
+	}
+
+	return nil
+}
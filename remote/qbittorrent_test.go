@@ -0,0 +1,115 @@
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+// fakeQbittorrent answers like the WebUI API, added receives the forms sent to
+// torrents/add.
+func fakeQbittorrent(t *testing.T, added *[]map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v2/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.FormValue("username") != "admin" || r.FormValue("password") != "adminadmin" {
+			w.Write([]byte("Fails."))
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "SID", Value: "session", Path: "/"})
+		w.Write([]byte("Ok."))
+	})
+
+	mux.HandleFunc("/api/v2/torrents/add", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("SID"); err != nil || cookie.Value != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if strings.Contains(r.FormValue("urls"), "unsupported") {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		*added = append(*added, map[string]string{
+			"urls":     r.FormValue("urls"),
+			"savepath": r.FormValue("savepath"),
+			"category": r.FormValue("category"),
+		})
+		w.Write([]byte("Ok."))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestQbittorrentAdd(t *testing.T) {
+	var added []map[string]string
+	server := fakeQbittorrent(t, &added)
+
+	client, err := New(&config.ClientProfile{
+		Type:              "qbittorrent",
+		URL:               server.URL + "/",
+		Username:          "admin",
+		Password:          "adminadmin",
+		DownloadDirectory: "/data/anime",
+		Category:          "anime",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Add("https://nyaa.si/download/1.torrent"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"urls": "https://nyaa.si/download/1.torrent", "savepath": "/data/anime", "category": "anime"}
+	if len(added) != 1 || added[0]["urls"] != want["urls"] || added[0]["savepath"] != want["savepath"] || added[0]["category"] != want["category"] {
+		t.Errorf("got %v, want %v", added, want)
+	}
+}
+
+func TestQbittorrentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		link     string
+		want     string
+	}{
+		{name: "wrong password", password: "wrong", link: "magnet:?xt=urn:btih:0", want: "qbittorrent login: /api/v2/auth/login: Fails."},
+		{name: "error status", password: "adminadmin", link: "magnet:?unsupported", want: "qbittorrent: /api/v2/torrents/add: 415 Unsupported Media Type"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var added []map[string]string
+			server := fakeQbittorrent(t, &added)
+
+			client, err := New(&config.ClientProfile{Type: "qbittorrent", URL: server.URL, Username: "admin", Password: test.password})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := client.Add(test.link); err == nil || err.Error() != test.want {
+				t.Errorf("got error %v, want %s", err, test.want)
+			}
+
+			if len(added) != 0 {
+				t.Errorf("torrent added anyway: %v", added)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+// Client adds torrents to a remote torrent client.
+type Client interface {
+	// Add sends the link of a .torrent file or a magnet link to the client.
+	Add(link string) error
+}
+
+func New(profile *config.ClientProfile) (Client, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Jar:     jar,
+		Timeout: 30 * time.Second,
+	}
+
+	url := strings.TrimSuffix(profile.URL, "/")
+
+	switch profile.Type {
+	case "qbittorrent":
+		return &qbittorrent{profile: profile, url: url, http: httpClient}, nil
+	case "transmission":
+		return &transmission{profile: profile, url: url, http: httpClient}, nil
+	case "deluge":
+		return &deluge{profile: profile, url: url, http: httpClient}, nil
+	}
+
+	return nil, fmt.Errorf("unknown client type %q", profile.Type)
+}
+
+// postJSON sends the body encoded in JSON and decodes the response in result.
+func postJSON(client *http.Client, url string, header http.Header, body interface{}, result interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("%s: %s", url, res.Status)
+	}
+
+	return res, json.NewDecoder(res.Body).Decode(result)
+}
@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+const transmissionSessionHeader = "X-Transmission-Session-Id"
+
+// transmission talks to the Transmission RPC, the url is the RPC endpoint like
+// http://localhost:9091/transmission/rpc.
+type transmission struct {
+	profile   *config.ClientProfile
+	url       string
+	http      *http.Client
+	sessionId string
+}
+
+type transmissionResponse struct {
+	Result string `json:"result"`
+}
+
+func (t *transmission) Add(link string) error {
+	arguments := map[string]interface{}{
+		"filename": link,
+	}
+	if t.profile.DownloadDirectory != "" {
+		arguments["download-dir"] = t.profile.DownloadDirectory
+	}
+	if t.profile.Category != "" {
+		arguments["labels"] = []string{t.profile.Category}
+	}
+
+	body := map[string]interface{}{
+		"method":    "torrent-add",
+		"arguments": arguments,
+	}
+
+	var result transmissionResponse
+
+	// The first request is answered with a 409 giving the session id to use
+	for attempt := 0; attempt < 2; attempt++ {
+		header := http.Header{}
+		header.Set(transmissionSessionHeader, t.sessionId)
+		if t.profile.Username != "" {
+			header.Set("Authorization", basicAuth(t.profile.Username, t.profile.Password))
+		}
+
+		res, err := postJSON(t.http, t.url, header, body, &result)
+		if res != nil && res.StatusCode == http.StatusConflict {
+			t.sessionId = res.Header.Get(transmissionSessionHeader)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("transmission: %w", err)
+		}
+
+		if result.Result != "success" {
+			return fmt.Errorf("transmission: %s", result.Result)
+		}
+
+		return nil
+	}
+
+	return errors.New("transmission: no session id returned")
+}
+
+func basicAuth(username string, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
@@ -0,0 +1,152 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/quantumsheep/nyaa-cli/config"
+)
+
+type transmissionRequest struct {
+	Method    string `json:"method"`
+	Arguments struct {
+		Filename    string   `json:"filename"`
+		DownloadDir string   `json:"download-dir"`
+		Labels      []string `json:"labels"`
+	} `json:"arguments"`
+}
+
+// fakeTransmission answers like the Transmission RPC, requiring the session id
+// handshake and basic authentication.
+func fakeTransmission(t *testing.T, requests *[]transmissionRequest, conflicts *int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get(transmissionSessionHeader) != "session" {
+			*conflicts++
+			w.Header().Set(transmissionSessionHeader, "session")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var request transmissionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*requests = append(*requests, request)
+
+		result := "success"
+		if strings.Contains(request.Arguments.Filename, "corrupt") {
+			result = "invalid or corrupt torrent file"
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTransmissionAdd(t *testing.T) {
+	var requests []transmissionRequest
+	conflicts := 0
+	server := fakeTransmission(t, &requests, &conflicts)
+
+	client, err := New(&config.ClientProfile{
+		Type:              "transmission",
+		URL:               server.URL,
+		Username:          "admin",
+		Password:          "secret",
+		DownloadDirectory: "/data/anime",
+		Category:          "anime",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range []string{"https://nyaa.si/download/1.torrent", "https://nyaa.si/download/2.torrent"} {
+		if err := client.Add(link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The session id is kept for the next requests
+	if conflicts != 1 {
+		t.Errorf("got %d session id handshakes, want 1", conflicts)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+
+	request := requests[0]
+	if request.Method != "torrent-add" || request.Arguments.Filename != "https://nyaa.si/download/1.torrent" || request.Arguments.DownloadDir != "/data/anime" || len(request.Arguments.Labels) != 1 || request.Arguments.Labels[0] != "anime" {
+		t.Errorf("unexpected request %+v", request)
+	}
+}
+
+func TestTransmissionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		link     string
+		want     string
+	}{
+		{name: "wrong password", password: "wrong", link: "magnet:?xt=urn:btih:0", want: "401 Unauthorized"},
+		{name: "rpc error", password: "secret", link: "https://nyaa.si/download/corrupt.torrent", want: "transmission: invalid or corrupt torrent file"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []transmissionRequest
+			conflicts := 0
+			server := fakeTransmission(t, &requests, &conflicts)
+
+			client, err := New(&config.ClientProfile{Type: "transmission", URL: server.URL, Username: "admin", Password: test.password})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := client.Add(test.link); err == nil || !strings.HasSuffix(err.Error(), test.want) {
+				t.Errorf("got error %v, want %s", err, test.want)
+			}
+		})
+	}
+}
+
+func TestTransmissionNoSessionId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	client, err := New(&config.ClientProfile{Type: "transmission", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Add("magnet:?xt=urn:btih:0"); err == nil || err.Error() != "transmission: no session id returned" {
+		t.Errorf("got error %v, want transmission: no session id returned", err)
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", basicAuth("admin", "p@ss:word"))
+
+	if username, password, ok := req.BasicAuth(); !ok || username != "admin" || password != "p@ss:word" {
+		t.Errorf("got %q and %q, want admin and p@ss:word", username, password)
+	}
+}
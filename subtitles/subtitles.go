@@ -0,0 +1,108 @@
+package subtitles
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/quantumsheep/nyaa-cli/parser"
+)
+
+var extensions = map[string]bool{
+	".ass": true,
+	".ssa": true,
+	".srt": true,
+	".vtt": true,
+	".sub": true,
+}
+
+func IsSubtitle(name string) bool {
+	return extensions[strings.ToLower(path.Ext(name))]
+}
+
+// Match returns the indices of the subtitle files among candidates that go
+// with the video, best matches first. Paths use forward slashes.
+func Match(video string, candidates []string) []int {
+	videoBase := trimExtension(path.Base(video))
+	videoRelease := parser.Parse(path.Base(video))
+
+	scores := make(map[int]int)
+	var matches []int
+
+	for i, candidate := range candidates {
+		if !IsSubtitle(candidate) {
+			continue
+		}
+
+		score := 0
+
+		// "Show - 05.mkv" goes with "Show - 05.ass" and "Show - 05.en.ass",
+		// but "Show - 1.mkv" doesn't go with "Show - 10.ass"
+		if base := trimExtension(path.Base(candidate)); base == videoBase || strings.HasPrefix(base, videoBase+".") {
+			score += 4
+		}
+
+		release := parser.Parse(trimExtension(path.Base(candidate)))
+		if videoRelease.Episode > 0 && release.Episode == videoRelease.Episode && release.Season == videoRelease.Season {
+			score += 2
+		}
+
+		if path.Dir(candidate) == path.Dir(video) {
+			score++
+		}
+
+		// Being in the same directory isn't enough when episodes are known
+		if score > 1 || (score == 1 && videoRelease.Episode == 0) {
+			scores[i] = score
+			matches = append(matches, i)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return scores[matches[i]] > scores[matches[j]]
+	})
+
+	return matches
+}
+
+// FindInDirectory returns the subtitle files of the directory and its
+// subdirectories that go with the video, best matches first.
+func FindInDirectory(directory string, video string) ([]string, error) {
+	var candidates []string
+
+	err := filepath.Walk(directory, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && IsSubtitle(p) {
+			candidates = append(candidates, filepath.ToSlash(p))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	videoRelease := parser.Parse(path.Base(video))
+
+	var files []string
+	for _, i := range Match(video, candidates) {
+		// Outside of the torrent, the episode alone could match another series
+		release := parser.Parse(trimExtension(path.Base(candidates[i])))
+		if release.Title != "" && videoRelease.Title != "" && !strings.EqualFold(release.Title, videoRelease.Title) {
+			continue
+		}
+
+		files = append(files, filepath.FromSlash(candidates[i]))
+	}
+
+	return files, nil
+}
+
+func trimExtension(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name))
+}
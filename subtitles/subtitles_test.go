@@ -0,0 +1,49 @@
+package subtitles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	candidates := []string{
+		"Show/Show - 1.mkv",
+		"Show/Show - 1.srt",
+		"Show/Show - 1.en.ass",
+		"Show/Show - 10.mkv",
+		"Show/Show - 10.srt",
+		"Show/Show - 10.en.ass",
+		"Show/Subs/Show - 10 [Group].ass",
+		"Show/notes.txt",
+	}
+
+	tests := []struct {
+		video string
+		want  []int
+	}{
+		{video: "Show/Show - 1.mkv", want: []int{1, 2}},
+		{video: "Show/Show - 10.mkv", want: []int{4, 5, 6}},
+	}
+
+	for _, test := range tests {
+		if got := Match(test.video, candidates); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Match(%q) = %v, want %v", test.video, got, test.want)
+		}
+	}
+}
+
+func TestMatchUnknownEpisode(t *testing.T) {
+	candidates := []string{
+		"Movie/Movie.mkv",
+		"Movie/Movie.ass",
+		"Movie/Other.srt",
+		"Movie/Subs/Movie Extras.srt",
+	}
+
+	// Without an episode, the subtitles of the same directory are kept after
+	// the ones named after the video
+	want := []int{1, 2}
+	if got := Match("Movie/Movie.mkv", candidates); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	columnId = iota
+	columnSaved
+	columnWatched
+	columnSize
+	columnDate
+	columnSeeders
+	columnLeechers
+	columnDownloads
+	columnTrusted
+	columnRemake
+	columnCategory
+	columnResolution
+	columnEpisode
+	columnName
+)
+
+type columnSpec struct {
+	// Name of the column in the configuration
+	key   string
+	title string
+	width int
+	// Columns with the lowest priority are hidden first when the terminal is
+	// too narrow
+	priority int
+	// Local sort applied when the header is clicked
+	sort string
+}
+
+var columns = []columnSpec{
+	columnId:         {key: "id", title: "ID", width: 8, priority: 5},
+	columnSaved:      {key: "saved", title: "", width: 4, priority: 8},
+	columnWatched:    {key: "watched", title: "", width: 2, priority: 9},
+	columnSize:       {key: "size", title: "Size", width: 10, priority: 10, sort: "Size"},
+	columnDate:       {key: "date", title: "Date", width: 17, priority: 4, sort: "Date"},
+	columnSeeders:    {key: "seeders", title: "S", width: 6, priority: 11, sort: "Seeders"},
+	columnLeechers:   {key: "leechers", title: "L", width: 6, priority: 3, sort: "Leechers"},
+	columnDownloads:  {key: "downloads", title: "C", width: 7, priority: 2, sort: "Downloads"},
+	columnTrusted:    {key: "trusted", title: "", width: 2, priority: 6},
+	columnRemake:     {key: "remake", title: "", width: 2, priority: 6},
+	columnCategory:   {key: "category", title: "Category", width: 24, priority: 1},
+	columnResolution: {key: "resolution", title: "Res", width: 6, priority: 7},
+	columnEpisode:    {key: "episode", title: "Ep", width: 8, priority: 7},
+	columnName:       {key: "name", title: "Name", width: 0, priority: 12, sort: "Name"},
+}
+
+// Width kept for the names when hiding columns on narrow terminals
+const minNameWidth = 30
+
+// LayoutColumns picks the configured columns fitting in the width and returns
+// whether they changed. The name column is always shown, last.
+func (ui *UI) LayoutColumns(width int) bool {
+	var visible []int
+	for _, key := range ui.options.Columns {
+		for column, spec := range columns {
+			if spec.key == key && column != columnName {
+				visible = append(visible, column)
+			}
+		}
+	}
+
+	// Borders and the spaces between the columns
+	used := func() int {
+		total := 2 + minNameWidth
+		for _, column := range visible {
+			total += columns[column].width + 1
+		}
+		return total
+	}
+
+	for len(visible) > 0 && used() > width {
+		lowest := 0
+		for i, column := range visible {
+			if columns[column].priority < columns[visible[lowest]].priority {
+				lowest = i
+			}
+		}
+
+		visible = append(visible[:lowest], visible[lowest+1:]...)
+	}
+
+	visible = append(visible, columnName)
+
+	if equalInts(visible, ui.visibleColumns) {
+		return false
+	}
+
+	ui.visibleColumns = visible
+	ui.columnIndexes = make(map[int]int)
+	for i, column := range visible {
+		ui.columnIndexes[column] = i
+	}
+
+	return true
+}
+
+// SetHeader renders the fixed header row, clicking a header sorts the loaded
+// results by its column.
+func (ui *UI) SetHeader() {
+	for _, column := range ui.visibleColumns {
+		spec := columns[column]
+
+		cell := ui.GenerateCell(spec.title, spec.width, 0, ui.theme.Header).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false)
+
+		switch column {
+		case columnName:
+			cell.SetAlign(tview.AlignLeft).SetExpansion(1)
+		case columnCategory:
+			cell.SetAlign(tview.AlignLeft)
+		}
+
+		if spec.sort != "" {
+			cell.SetClickedFunc(func() bool {
+				ui.SortByHeader(spec.sort)
+				return true
+			})
+		}
+
+		ui.setCell(0, column, cell)
+	}
+
+	ui.table.SetFixed(1, 0)
+}
+
+// SortByHeader sorts by the column, or reverses the order when the results
+// are sorted by it already.
+func (ui *UI) SortByHeader(sort string) {
+	if localSortOptions[ui.localSort] == sort {
+		ui.ReverseLocalSort()
+		return
+	}
+
+	ui.localSort = optionIndex(localSortOptions, optionValue(sort))
+	ui.localSortAscending = false
+	ui.FillTable()
+	ui.NotifyLocalSort()
+}
+
+// setCell sets the cell of a column when it is visible.
+func (ui *UI) setCell(row int, column int, cell *tview.TableCell) {
+	if i, ok := ui.columnIndexes[column]; ok {
+		ui.table.SetCell(row, i, cell)
+	}
+}
+
+// getCell returns the cell of a visible column, nil when it is hidden.
+func (ui *UI) getCell(row int, column int) *tview.TableCell {
+	if i, ok := ui.columnIndexes[column]; ok {
+		return ui.table.GetCell(row, i)
+	}
+
+	return nil
+}
+
+func equalInts(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
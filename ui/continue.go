@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func (ui *UI) GenerateContinueWatchingPage() {
+	ui.continueWatching = tview.NewList().
+		ShowSecondaryText(true)
+
+	ui.continueWatching.
+		SetBorder(true).
+		SetTitle(" Continue watching (" + ui.KeyHints(contextContinue) + ") ").
+		SetBackgroundColor(tcell.ColorReset)
+
+	ui.continueWatching.SetDoneFunc(func() {
+		ui.pages.SwitchToPage("search")
+		ui.app.SetFocus(ui.table)
+	})
+
+	ui.continueWatching.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action, event := ui.Action(contextContinue, event)
+		if action != actionRemove {
+			return event
+		}
+
+		index := ui.continueWatching.GetCurrentItem()
+		if index < len(ui.watched.Entries) {
+			entry := ui.watched.Entries[index]
+			ui.watched.Remove(entry.GUID, entry.FileIndex)
+
+			if err := ui.watched.Save(); err != nil {
+				ui.Fatal(err)
+			}
+		}
+
+		ui.ShowContinueWatching()
+		ui.continueWatching.SetCurrentItem(index)
+
+		return nil
+	})
+
+	ui.pages.AddPage("continue", ui.continueWatching, true, false)
+}
+
+// ShowContinueWatching lists the watch history, most recent first.
+func (ui *UI) ShowContinueWatching() {
+	ui.continueWatching.Clear()
+
+	for _, entry := range ui.watched.Entries {
+		entry := entry
+
+		description := "watched on " + entry.WatchedAt.Format("2006-01-02 15:04")
+		if entry.Position > 0 {
+			description += ", stopped at " + formatSeconds(entry.Position)
+			if entry.Duration > 0 {
+				description += " / " + formatSeconds(entry.Duration)
+			}
+		}
+
+		ui.continueWatching.AddItem(tview.Escape(entry.Name), description, 0, func() {
+			ui.Play(entry.GUID, entry.Link, entry.FileIndex, entry.FilePath)
+			ui.ShowContinueWatching()
+		})
+	}
+
+	ui.pages.SwitchToPage("continue")
+}
+
+func formatSeconds(seconds float64) string {
+	d := time.Duration(seconds) * time.Second
+
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -0,0 +1,304 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/quantumsheep/nyaa-cli/engine"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/rivo/tview"
+)
+
+// States of the queued downloads
+const (
+	downloadQueued = iota
+	downloadRunning
+	downloadDone
+	downloadFailed
+	downloadCancelled
+)
+
+var downloadStates = []string{"queued", "downloading", "done", "failed", "cancelled"}
+
+type download struct {
+	torrent *Torrent
+	// Paths of the files to download, every file when empty
+	files []string
+
+	// Cancelled from the downloads page
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Only read and written on the UI goroutine
+	state int
+	err   error
+}
+
+// QueueDownload adds the torrent to the downloads made in the background into
+// the output directory.
+func (ui *UI) QueueDownload(torrent *Torrent, files []string) {
+	ui.downloadJobs = append(ui.downloadJobs, ui.startDownload(torrent, files))
+	ui.FillDownloads()
+}
+
+// startDownload queues a new download for RunDownloads. A retried download is
+// a new one, the cancelled one may still be waiting in the queue.
+func (ui *UI) startDownload(torrent *Torrent, files []string) *download {
+	if ui.downloadsQueued == nil {
+		ui.downloadsQueued = make(chan struct{}, 1)
+		go ui.RunDownloads()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &download{torrent: torrent, files: files, ctx: ctx, cancel: cancel}
+
+	ui.downloadsMutex.Lock()
+	ui.pendingDownloads = append(ui.pendingDownloads, d)
+	ui.downloadsMutex.Unlock()
+
+	// Never blocks the event loop, RunDownloads is already woken up when the
+	// channel is full
+	select {
+	case ui.downloadsQueued <- struct{}{}:
+	default:
+	}
+
+	return d
+}
+
+// nextDownload takes the first queued download, nil when there is none, and
+// returns the number of downloads left in the queue.
+func (ui *UI) nextDownload() (*download, int) {
+	ui.downloadsMutex.Lock()
+	defer ui.downloadsMutex.Unlock()
+
+	if len(ui.pendingDownloads) == 0 {
+		return nil, 0
+	}
+
+	d := ui.pendingDownloads[0]
+	ui.pendingDownloads = ui.pendingDownloads[1:]
+
+	return d, len(ui.pendingDownloads)
+}
+
+// QueueDownloads queues the torrents, or their ticked files.
+func (ui *UI) QueueDownloads(torrents []*Torrent) {
+	for _, torrent := range torrents {
+		ui.QueueDownload(torrent, torrent.SelectedFiles())
+	}
+
+	if len(torrents) > 0 {
+		ui.Notify(fmt.Sprintf("Queued %d downloads", len(torrents)))
+	}
+}
+
+// RunDownloads downloads the queued torrents one at a time and indexes their
+// videos in the library.
+func (ui *UI) RunDownloads() {
+	e, err := engine.NewEngine(ui.options.OutputDirectory)
+	if err != nil {
+		ui.app.QueueUpdateDraw(func() {
+			ui.Notify(err.Error())
+		})
+		return
+	}
+
+	// A dead torrent fails instead of blocking the next ones
+	e.StallTimeout = ui.options.StallTimeout
+
+	for range ui.downloadsQueued {
+		for {
+			d, queued := ui.nextDownload()
+			if d == nil {
+				break
+			}
+
+			ui.runDownload(e, d, queued)
+		}
+	}
+}
+
+// runDownload downloads a queued torrent, queued being the number of
+// downloads waiting after it.
+func (ui *UI) runDownload(e *engine.Engine, d *download, queued int) {
+	torrent := d.torrent
+
+	// Cancelled while queued
+	if d.ctx.Err() != nil {
+		return
+	}
+
+	ui.app.QueueUpdateDraw(func() {
+		d.state = downloadRunning
+		ui.FillDownloads()
+		ui.Notify(fmt.Sprintf("Downloading %s (%d more queued)", torrent.Name, queued))
+	})
+
+	items, err := downloadTorrent(d.ctx, e, torrent, d.files)
+
+	ui.app.QueueUpdateDraw(func() {
+		defer ui.FillDownloads()
+		d.cancel()
+
+		if errors.Is(err, context.Canceled) {
+			d.state = downloadCancelled
+			ui.Notify("Cancelled " + torrent.Name)
+			return
+		}
+
+		if err != nil {
+			d.state = downloadFailed
+			d.err = err
+			ui.Notify(fmt.Sprintf("%s: %s", torrent.Name, err))
+			return
+		}
+
+		for _, item := range items {
+			ui.library.Add(item)
+		}
+
+		if err := ui.library.Save(); err != nil {
+			ui.Fatal(err)
+		}
+
+		d.state = downloadDone
+		ui.Notify("Downloaded " + torrent.Name)
+	})
+}
+
+// downloadTorrent downloads the files of the torrent at the given paths, or
+// the whole torrent when files is empty, and returns the library items of the
+// downloaded videos.
+func downloadTorrent(ctx context.Context, e *engine.Engine, torrent *Torrent, paths []string) ([]library.Item, error) {
+	if err := e.SetTorrentFromPath(torrent.Link); err != nil {
+		return nil, err
+	}
+	defer e.DropCurrentTorrent()
+
+	var files []int
+	for _, path := range paths {
+		index, err := e.FileIndex(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, index)
+	}
+
+	if len(files) == 0 {
+		if err := e.DownloadAll(ctx); err != nil {
+			return nil, err
+		}
+	} else if err := e.DownloadFiles(ctx, files); err != nil {
+		return nil, err
+	}
+
+	var items []library.Item
+	for i, path := range e.FilePaths() {
+		if !library.IsVideo(path) || (len(files) > 0 && !containsIndex(files, i)) {
+			continue
+		}
+
+		localPath := e.LocalPath(i)
+
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return nil, err
+		}
+
+		item := library.NewItem(localPath, info.Size())
+		item.GUID = torrent.GUID
+		item.InfoHash = e.InfoHash()
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func containsIndex(indices []int, index int) bool {
+	for _, i := range indices {
+		if i == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ui *UI) GenerateDownloadsPage() {
+	ui.downloadList = tview.NewList().
+		ShowSecondaryText(true)
+
+	ui.downloadList.
+		SetBorder(true).
+		SetTitle(" Downloads (" + ui.KeyHints(contextDownloads) + ") ").
+		SetBackgroundColor(tcell.ColorReset)
+
+	ui.downloadList.SetDoneFunc(func() {
+		ui.pages.SwitchToPage("search")
+		ui.app.SetFocus(ui.table)
+	})
+
+	ui.downloadList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action, event := ui.Action(contextDownloads, event)
+		if action != actionCancel && action != actionRetry {
+			return event
+		}
+
+		index := ui.downloadList.GetCurrentItem()
+		if index >= len(ui.downloadJobs) {
+			return nil
+		}
+
+		d := ui.downloadJobs[index]
+		switch {
+		case action == actionRetry && (d.state == downloadFailed || d.state == downloadCancelled):
+			ui.downloadJobs[index] = ui.startDownload(d.torrent, d.files)
+			ui.FillDownloads()
+		case action == actionCancel && d.state == downloadQueued:
+			d.cancel()
+			d.state = downloadCancelled
+			ui.FillDownloads()
+		case action == actionCancel && d.state == downloadRunning:
+			// Marked as cancelled by RunDownloads once the engine stopped,
+			// which frees the queue for the next download
+			d.cancel()
+		}
+
+		return nil
+	})
+
+	ui.pages.AddPage("downloads", ui.downloadList, true, false)
+}
+
+// ShowDownloads lists the downloads queued since the interface was opened.
+func (ui *UI) ShowDownloads() {
+	ui.FillDownloads()
+	ui.downloadList.SetCurrentItem(0)
+	ui.pages.SwitchToPage("downloads")
+}
+
+// FillDownloads lists the downloads and their state, keeping the current item.
+func (ui *UI) FillDownloads() {
+	current := ui.downloadList.GetCurrentItem()
+	ui.downloadList.Clear()
+
+	for _, d := range ui.downloadJobs {
+		description := downloadStates[d.state]
+		if len(d.files) > 0 {
+			description += fmt.Sprintf(", %d files", len(d.files))
+		}
+		if d.err != nil {
+			description += ": " + d.err.Error()
+		}
+
+		ui.downloadList.AddItem(tview.Escape(d.torrent.Name), tview.Escape(description), 0, nil)
+	}
+
+	ui.downloadList.SetCurrentItem(current)
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dustin/go-humanize"
+	"github.com/gocolly/colly"
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+type torrentFile struct {
+	name string
+	// Path of the file in the torrent, starting with the torrent name, the
+	// position in the lists of nyaa doesn't match the index in the metainfo
+	path string
+	size string
+
+	// Length in bytes and first and last pieces holding the file, only known
+	// when listed from the metainfo
+	length     int64
+	firstPiece int
+	lastPiece  int
+}
+
+// torrentFiles lists the files of the torrent from its .torrent file, or from
+// its page on nyaa when the .torrent file can't be read.
+func torrentFiles(torrent *Torrent) ([]*torrentFile, error) {
+	files, err := metainfoTorrentFiles(torrent.Link)
+	if err == nil {
+		return files, nil
+	}
+
+	return nyaaTorrentFiles(torrent.GUID)
+}
+
+// metainfoTorrentFiles lists the files of the .torrent file in the order of
+// the metainfo, which is the order of the indices of the engine.
+func metainfoTorrentFiles(link string) ([]*torrentFile, error) {
+	mi, err := utils.LoadMetainfo(link)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*torrentFile
+
+	// The files follow each other in the pieces
+	offset := int64(0)
+	for _, fi := range info.UpvertedFiles() {
+		end := offset + fi.Length
+		if fi.Length > 0 {
+			end--
+		}
+
+		files = append(files, &torrentFile{
+			name:       fi.DisplayPath(&info),
+			path:       strings.Join(append([]string{info.BestName()}, fi.BestPath()...), "/"),
+			size:       humanize.IBytes(uint64(fi.Length)),
+			length:     fi.Length,
+			firstPiece: int(offset / info.PieceLength),
+			lastPiece:  int(end / info.PieceLength),
+		})
+
+		offset += fi.Length
+	}
+
+	return files, nil
+}
+
+// nyaaTorrentFiles scrapes the file list of the torrent page.
+func nyaaTorrentFiles(viewURL string) ([]*torrentFile, error) {
+	var files []*torrentFile
+
+	c := colly.NewCollector()
+	c.WithTransport(utils.HTTPClient().Transport)
+	c.SetRequestTimeout(utils.HTTPClient().Timeout)
+
+	c.OnHTML(".torrent-file-list li", func(e *colly.HTMLElement) {
+		if e.DOM.Has("ul").Length() == 0 {
+			fileSize := e.ChildText(".file-size")
+			name := strings.TrimSuffix(e.Text, " "+fileSize)
+
+			// The folders are the parent items, the closest first
+			path := []string{strings.TrimSpace(name)}
+			e.DOM.ParentsFiltered("li").Each(func(_ int, folder *goquery.Selection) {
+				path = append([]string{strings.TrimSpace(folder.ChildrenFiltered(".folder").Text())}, path...)
+			})
+
+			files = append(files, &torrentFile{
+				name: name,
+				path: strings.Join(path, "/"),
+				size: fileSize[1 : len(fileSize)-1], // remove "(" and ")"
+			})
+		}
+	})
+
+	var e error
+	c.OnError(func(r *colly.Response, err error) {
+		e = err
+	})
+
+	err := c.Visit(viewURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		return nil, e
+	}
+
+	return files, nil
+}
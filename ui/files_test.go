@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestMetainfoTorrentFiles(t *testing.T) {
+	// The downloaded .torrent is cached in the user cache directory
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	info := metainfo.Info{
+		Name:        "Show",
+		PieceLength: 16,
+		Pieces:      make([]byte, 3*20),
+		Files: []metainfo.FileInfo{
+			{Path: []string{"Show - 01.mkv"}, Length: 20},
+			{Path: []string{"Subs", "Show - 01.ass"}, Length: 10},
+			{Path: []string{"empty.txt"}, Length: 0},
+			{Path: []string{"Show - 02.mkv"}, Length: 16},
+		},
+	}
+
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		mi := metainfo.MetaInfo{InfoBytes: infoBytes}
+		if err := mi.Write(w); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	want := []torrentFile{
+		{name: "Show - 01.mkv", path: "Show/Show - 01.mkv", size: "20 B", length: 20, firstPiece: 0, lastPiece: 1},
+		{name: "Subs/Show - 01.ass", path: "Show/Subs/Show - 01.ass", size: "10 B", length: 10, firstPiece: 1, lastPiece: 1},
+		{name: "empty.txt", path: "Show/empty.txt", size: "0 B", length: 0, firstPiece: 1, lastPiece: 1},
+		{name: "Show - 02.mkv", path: "Show/Show - 02.mkv", size: "16 B", length: 16, firstPiece: 1, lastPiece: 2},
+	}
+
+	// The second listing reads the cached .torrent
+	for i := 0; i < 2; i++ {
+		files, err := metainfoTorrentFiles(server.URL + "/download/1.torrent")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(files) != len(want) {
+			t.Fatalf("got %d files, want %d", len(files), len(want))
+		}
+
+		for j, file := range files {
+			if *file != want[j] {
+				t.Errorf("file %d: got %+v, want %+v", j, *file, want[j])
+			}
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("the .torrent was downloaded %d times, want once", requests)
+	}
+}
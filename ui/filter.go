@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Columns the loaded results can be sorted by without searching again
+var localSortOptions = []string{"Default", "Name", "Size", "Date", "Seeders", "Leechers", "Downloads"}
+
+// localFilter narrows the loaded results. Its text is made of words matched
+// against the name and of the size:, seeders:, res: and trusted terms.
+type localFilter struct {
+	words      []string
+	minSize    uint64
+	maxSize    uint64
+	minSeeders int
+	resolution string
+	trusted    bool
+}
+
+func parseLocalFilter(text string) (*localFilter, error) {
+	filter := &localFilter{}
+
+	for _, term := range strings.Fields(strings.ToLower(text)) {
+		key, value, ok := strings.Cut(term, ":")
+		if !ok {
+			if term == "trusted" {
+				filter.trusted = true
+			} else {
+				filter.words = append(filter.words, term)
+			}
+
+			continue
+		}
+
+		var err error
+		switch key {
+		case "size":
+			filter.minSize, filter.maxSize, err = parseSizeRange(value)
+		case "seeders":
+			filter.minSeeders, err = strconv.Atoi(strings.TrimPrefix(value, ">"))
+		case "res":
+			filter.resolution = value
+		case "trusted":
+			filter.trusted = value == "yes" || value == "true"
+		default:
+			filter.words = append(filter.words, term)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s filter %q", key, value)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseSizeRange parses ">1GB", "<500MB" or "500MB-2GB", a zero maximum
+// means no limit.
+func parseSizeRange(value string) (uint64, uint64, error) {
+	if strings.HasPrefix(value, ">") {
+		min, err := humanize.ParseBytes(value[1:])
+		return min, 0, err
+	}
+
+	if strings.HasPrefix(value, "<") {
+		max, err := humanize.ParseBytes(value[1:])
+		return 0, max, err
+	}
+
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid size range %q", value)
+	}
+
+	min, err := humanize.ParseBytes(from)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	max, err := humanize.ParseBytes(to)
+	return min, max, err
+}
+
+func (f *localFilter) matches(torrent *Torrent) bool {
+	name := strings.ToLower(torrent.Name)
+	for _, word := range f.words {
+		if !strings.Contains(name, word) {
+			return false
+		}
+	}
+
+	size := torrentSize(torrent)
+	if size < f.minSize || (f.maxSize > 0 && size > f.maxSize) {
+		return false
+	}
+
+	if seeders, _ := strconv.Atoi(torrent.Seeders); seeders < f.minSeeders {
+		return false
+	}
+
+	if f.resolution != "" && strings.ToLower(torrent.release.Resolution) != f.resolution {
+		return false
+	}
+
+	return !f.trusted || torrent.IsTrusted == "Yes"
+}
+
+func (ui *UI) GenerateFilterBar() {
+	ui.filterBar = tview.NewInputField().
+		SetLabel("/").
+		SetPlaceholder("words size:>1GB size:500MB-2GB seeders:10 res:1080p trusted").
+		SetFieldBackgroundColor(tcell.ColorReset)
+
+	ui.filterBar.SetChangedFunc(func(text string) {
+		filter, err := parseLocalFilter(text)
+		if err != nil {
+			ui.filterBar.SetFieldTextColor(ui.theme.Error)
+			return
+		}
+
+		ui.filterBar.SetFieldTextColor(ui.theme.Text)
+		ui.localFilter = filter
+		ui.FillTable()
+	})
+
+	ui.filterBar.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if ui.typing(event) {
+			return event
+		}
+
+		_, event = ui.Action(contextFilter, event)
+		return event
+	})
+
+	ui.filterBar.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			ui.filterBar.SetText("")
+			ui.layout.ResizeItem(ui.filterBar, 0, 0)
+		}
+
+		ui.app.SetFocus(ui.table)
+	})
+}
+
+// ShowFilterBar opens the bar filtering the loaded results.
+func (ui *UI) ShowFilterBar() {
+	ui.layout.ResizeItem(ui.filterBar, 1, 0)
+	ui.app.SetFocus(ui.filterBar)
+}
+
+// CycleLocalSort sorts the loaded results by the next column.
+func (ui *UI) CycleLocalSort() {
+	ui.localSort = (ui.localSort + 1) % len(localSortOptions)
+	ui.FillTable()
+	ui.NotifyLocalSort()
+}
+
+// ReverseLocalSort flips the order of the loaded results.
+func (ui *UI) ReverseLocalSort() {
+	ui.localSortAscending = !ui.localSortAscending
+	ui.FillTable()
+	ui.NotifyLocalSort()
+}
+
+func (ui *UI) NotifyLocalSort() {
+	order := "↓"
+	if ui.localSortAscending {
+		order = "↑"
+	}
+
+	ui.Notify(fmt.Sprintf("Sorted by %s %s", strings.ToLower(localSortOptions[ui.localSort]), order))
+}
+
+// sortTorrents sorts the torrents by the local sort column, descending unless
+// ascending is set.
+func (ui *UI) sortTorrents(torrents []*Torrent) {
+	var less func(a, b *Torrent) bool
+
+	switch localSortOptions[ui.localSort] {
+	case "Name":
+		less = func(a, b *Torrent) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "Size":
+		less = func(a, b *Torrent) bool { return torrentSize(a) < torrentSize(b) }
+	case "Date":
+		less = func(a, b *Torrent) bool { return torrentDate(a).Before(torrentDate(b)) }
+	case "Seeders":
+		less = func(a, b *Torrent) bool { return atoi(a.Seeders) < atoi(b.Seeders) }
+	case "Leechers":
+		less = func(a, b *Torrent) bool { return atoi(a.Leechers) < atoi(b.Leechers) }
+	case "Downloads":
+		less = func(a, b *Torrent) bool { return atoi(a.Downloads) < atoi(b.Downloads) }
+	default:
+		// Keep the order of the search
+		if ui.localSortAscending {
+			for i, j := 0, len(torrents)-1; i < j; i, j = i+1, j-1 {
+				torrents[i], torrents[j] = torrents[j], torrents[i]
+			}
+		}
+		return
+	}
+
+	sort.SliceStable(torrents, func(i, j int) bool {
+		if ui.localSortAscending {
+			return less(torrents[i], torrents[j])
+		}
+
+		return less(torrents[j], torrents[i])
+	})
+}
+
+func torrentSize(torrent *Torrent) uint64 {
+	size, _ := humanize.ParseBytes(torrent.Size)
+	return size
+}
+
+func torrentDate(torrent *Torrent) time.Time {
+	t, _ := time.Parse("Mon, 02 Jan 2006 15:04:05 -0700", torrent.Date)
+	return t
+}
+
+func atoi(value string) int {
+	i, _ := strconv.Atoi(value)
+	return i
+}
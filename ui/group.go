@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prefixes of the names in the grouped view
+const (
+	collapsedGroupPrefix = "▸ "
+	expandedGroupPrefix  = "▾ "
+	alternativePrefix    = "└ "
+)
+
+// groupTorrents clusters the torrents by series and episode. Groups keep the
+// order of their first torrent and start with their best release.
+func groupTorrents(torrents []*Torrent) [][]*Torrent {
+	var groups [][]*Torrent
+	indexes := make(map[string]int)
+
+	for _, torrent := range torrents {
+		key := groupKey(torrent)
+
+		if i, ok := indexes[key]; ok {
+			groups[i] = append(groups[i], torrent)
+			continue
+		}
+
+		indexes[key] = len(groups)
+		groups = append(groups, []*Torrent{torrent})
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return releaseScore(group[i]) > releaseScore(group[j])
+		})
+	}
+
+	return groups
+}
+
+func groupKey(torrent *Torrent) string {
+	release := torrent.release
+
+	// Names that couldn't be parsed are never grouped together
+	if release.Title == "" {
+		return "id:" + torrent.id
+	}
+
+	return fmt.Sprintf("%s|%d|%d|%d|%t", strings.ToLower(release.Title), release.Season, release.Episode, release.EpisodeEnd, release.Batch)
+}
+
+// groupPrefix marks the best release of a group, which hides the other ones
+// unless expanded.
+func groupPrefix(size int, expanded bool) string {
+	if size == 1 {
+		return ""
+	}
+
+	if expanded {
+		return expandedGroupPrefix
+	}
+
+	return collapsedGroupPrefix
+}
+
+// ToggleGroup shows or hides the alternatives of the group of the row, the
+// cursor stays on the best release of the group.
+func (ui *UI) ToggleGroup(row int) {
+	r := ui.Row(row)
+	if r == nil || viewOptions[ui.view] != "Grouped" {
+		return
+	}
+
+	key := groupKey(r.torrent)
+
+	if ui.expandedGroups == nil {
+		ui.expandedGroups = make(map[string]bool)
+	}
+	ui.expandedGroups[key] = !ui.expandedGroups[key]
+
+	ui.FillTable()
+
+	for row := 1; row < ui.table.GetRowCount(); row++ {
+		if r := ui.Row(row); r != nil && r.index == -1 && groupKey(r.torrent) == key {
+			ui.table.Select(row, 0)
+			return
+		}
+	}
+}
+
+// releaseScore ranks trusted releases first, then the most seeded ones.
+func releaseScore(torrent *Torrent) int {
+	score, _ := strconv.Atoi(torrent.Seeders)
+
+	if torrent.IsTrusted == "Yes" {
+		score += 1 << 30
+	}
+
+	return score
+}
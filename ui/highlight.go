@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// highlightPattern matches the terms of the searched query and of the local
+// filter in the names, nil when there is nothing to highlight.
+func highlightPattern(query string, filter *localFilter) *regexp.Regexp {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		// Excluded words and operators of the nyaa search syntax
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+
+		for _, term := range strings.Split(field, "|") {
+			term = strings.Trim(term, `"()`)
+			if term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	if filter != nil {
+		terms = append(terms, filter.words...)
+	}
+
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Longest terms first so they win over the terms they contain
+	sort.Slice(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+
+	for i, term := range terms {
+		terms[i] = regexp.QuoteMeta(term)
+	}
+
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// HighlightName escapes the name and wraps the matched terms in a color tag.
+func (ui *UI) HighlightName(name string) string {
+	if ui.highlight == nil {
+		return tview.Escape(name)
+	}
+
+	tag := styleTag(ui.theme.Match, ui.theme.MatchAttributes)
+
+	var b strings.Builder
+	last := 0
+	for _, match := range ui.highlight.FindAllStringIndex(name, -1) {
+		b.WriteString(tview.Escape(name[last:match[0]]))
+		b.WriteString(tag)
+		b.WriteString(tview.Escape(name[match[0]:match[1]]))
+		b.WriteString("[-::-]")
+		last = match[1]
+	}
+	b.WriteString(tview.Escape(name[last:]))
+
+	return b.String()
+}
+
+// GenerateNameCell renders a torrent or file name with the matched terms
+// highlighted.
+func (ui *UI) GenerateNameCell(prefix string, name string, color tcell.Color) *tview.TableCell {
+	return tview.NewTableCell(tview.Escape(prefix) + ui.HighlightName(name)).
+		SetTextColor(color).
+		SetAlign(tview.AlignLeft).
+		SetExpansion(1)
+}
+
+// styleTag returns the tview tag setting the color and attributes, the
+// default color keeps the color of the cell.
+func styleTag(color tcell.Color, attributes tcell.AttrMask) string {
+	foreground := ""
+	if color != tcell.ColorDefault {
+		foreground = fmt.Sprintf("#%06x", color.Hex())
+
+		// Named colors follow the palette of the terminal
+		for name, named := range tcell.ColorNames {
+			if named == color && !color.IsRGB() {
+				foreground = name
+				break
+			}
+		}
+	}
+
+	flags := ""
+	for _, flag := range []struct {
+		attribute tcell.AttrMask
+		letter    string
+	}{
+		{tcell.AttrBold, "b"},
+		{tcell.AttrUnderline, "u"},
+		{tcell.AttrReverse, "r"},
+		{tcell.AttrItalic, "i"},
+		{tcell.AttrDim, "d"},
+	} {
+		if attributes&flag.attribute != 0 {
+			flags += flag.letter
+		}
+	}
+
+	return "[" + foreground + "::" + flags + "]"
+}
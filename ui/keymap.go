@@ -0,0 +1,374 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Widgets the key bindings apply to, global bindings apply on the search page
+const (
+	contextGlobal    = "global"
+	contextForm      = "form"
+	contextResults   = "results"
+	contextFilter    = "filter"
+	contextSearches  = "searches"
+	contextContinue  = "continue"
+	contextLibrary   = "library"
+	contextDownloads = "downloads"
+)
+
+var contextTitles = map[string]string{
+	contextGlobal:    "Everywhere",
+	contextForm:      "Search form",
+	contextResults:   "Results",
+	contextFilter:    "Filter bar",
+	contextSearches:  "Searches",
+	contextContinue:  "Continue watching",
+	contextLibrary:   "Library",
+	contextDownloads: "Downloads",
+}
+
+const (
+	actionSearches         = "searches"
+	actionSaveSearch       = "save-search"
+	actionContinueWatching = "continue-watching"
+	actionLibrary          = "library"
+	actionDownloads        = "downloads"
+	actionHelp             = "help"
+	actionQuit             = "quit"
+	actionNextField        = "next-field"
+	actionOpen             = "open"
+	actionExpand           = "expand"
+	actionCollapse         = "collapse"
+	actionToggleGroup      = "toggle-group"
+	actionSelect           = "select"
+	actionSelectAll        = "select-all"
+	actionSaveTorrent      = "save-torrent"
+	actionDownload         = "download"
+	actionCopyMagnet       = "copy-magnet"
+	actionCopyInfoHash     = "copy-infohash"
+	actionSend             = "send"
+	actionFilter           = "filter"
+	actionSort             = "sort"
+	actionReverseSort      = "reverse-sort"
+	actionBack             = "back"
+	actionApply            = "apply"
+	actionClear            = "clear"
+	actionRemove           = "remove"
+	actionCancel           = "cancel"
+	actionRetry            = "retry"
+	actionUp               = "up"
+	actionDown             = "down"
+	actionTop              = "top"
+	actionBottom           = "bottom"
+	actionPageUp           = "page-up"
+	actionPageDown         = "page-down"
+)
+
+type action struct {
+	context     string
+	name        string
+	description string
+	// Default keys, named like tcell names them ("F2", "Ctrl-A", "Enter") or
+	// the character typed
+	keys []string
+	// Key of the tview widget the action is translated to, zero for the
+	// actions handled by nyaa-cli
+	native tcell.Key
+}
+
+var actions = concatActions(
+	[]action{
+		{context: contextGlobal, name: actionSearches, description: "Searches", keys: []string{"F3"}},
+		{context: contextGlobal, name: actionSaveSearch, description: "Save search", keys: []string{"F4"}},
+		{context: contextGlobal, name: actionContinueWatching, description: "Continue watching", keys: []string{"F5"}},
+		{context: contextGlobal, name: actionLibrary, description: "Library", keys: []string{"F6"}},
+		{context: contextGlobal, name: actionDownloads, description: "Downloads", keys: []string{"F10"}},
+		{context: contextGlobal, name: actionHelp, description: "Help", keys: []string{"?"}},
+		{context: contextGlobal, name: actionQuit, description: "Quit", keys: []string{"Ctrl-C"}},
+
+		{context: contextForm, name: actionNextField, description: "Next field", keys: []string{"Tab"}, native: tcell.KeyTab},
+		{context: contextForm, name: actionBack, description: "Results", keys: []string{"Esc"}, native: tcell.KeyEscape},
+
+		{context: contextResults, name: actionOpen, description: "Play / show files", keys: []string{"Enter"}},
+		{context: contextResults, name: actionExpand, description: "Show files", keys: []string{"Right"}},
+		{context: contextResults, name: actionCollapse, description: "Hide files", keys: []string{"Left"}},
+		{context: contextResults, name: actionToggleGroup, description: "Alternatives", keys: []string{"a"}},
+		{context: contextResults, name: actionSelect, description: "Select", keys: []string{"Space"}},
+		{context: contextResults, name: actionSelectAll, description: "Select all", keys: []string{"Ctrl-A"}},
+		{context: contextResults, name: actionSaveTorrent, description: "Save .torrent", keys: []string{"F2"}},
+		{context: contextResults, name: actionDownload, description: "Download", keys: []string{"d"}},
+		{context: contextResults, name: actionCopyMagnet, description: "Copy magnet", keys: []string{"F7"}},
+		{context: contextResults, name: actionCopyInfoHash, description: "Copy infohash", keys: []string{"F8"}},
+		{context: contextResults, name: actionSend, description: "Send to client", keys: []string{"F9"}},
+		{context: contextResults, name: actionFilter, description: "Filter", keys: []string{"/"}},
+		{context: contextResults, name: actionSort, description: "Sort", keys: []string{"s"}},
+		{context: contextResults, name: actionReverseSort, description: "Reverse sort", keys: []string{"S"}},
+		{context: contextResults, name: actionBack, description: "Search form", keys: []string{"Esc", "Tab", "Backtab"}},
+	},
+	navigationActions(contextResults),
+	[]action{
+		{context: contextFilter, name: actionApply, description: "Apply", keys: []string{"Enter"}, native: tcell.KeyEnter},
+		{context: contextFilter, name: actionClear, description: "Clear", keys: []string{"Esc"}, native: tcell.KeyEscape},
+
+		{context: contextSearches, name: actionOpen, description: "Search", keys: []string{"Enter"}, native: tcell.KeyEnter},
+		{context: contextSearches, name: actionRemove, description: "Remove", keys: []string{"Delete"}},
+		{context: contextSearches, name: actionBack, description: "Back", keys: []string{"Esc"}, native: tcell.KeyEscape},
+	},
+	navigationActions(contextSearches),
+	[]action{
+		{context: contextContinue, name: actionOpen, description: "Play", keys: []string{"Enter"}, native: tcell.KeyEnter},
+		{context: contextContinue, name: actionRemove, description: "Remove", keys: []string{"Delete"}},
+		{context: contextContinue, name: actionBack, description: "Back", keys: []string{"Esc"}, native: tcell.KeyEscape},
+	},
+	navigationActions(contextContinue),
+	[]action{
+		{context: contextLibrary, name: actionOpen, description: "Play", keys: []string{"Enter"}, native: tcell.KeyEnter},
+		{context: contextLibrary, name: actionRemove, description: "Delete file", keys: []string{"Delete"}},
+		{context: contextLibrary, name: actionBack, description: "Back", keys: []string{"Esc"}, native: tcell.KeyEscape},
+	},
+	navigationActions(contextLibrary),
+	[]action{
+		{context: contextDownloads, name: actionCancel, description: "Cancel", keys: []string{"Delete"}},
+		{context: contextDownloads, name: actionRetry, description: "Retry", keys: []string{"r"}},
+		{context: contextDownloads, name: actionBack, description: "Back", keys: []string{"Esc"}, native: tcell.KeyEscape},
+	},
+	navigationActions(contextDownloads),
+)
+
+// navigationActions moves through the rows of the tables and lists.
+func navigationActions(context string) []action {
+	return []action{
+		{context: context, name: actionUp, description: "Up", keys: []string{"Up"}, native: tcell.KeyUp},
+		{context: context, name: actionDown, description: "Down", keys: []string{"Down"}, native: tcell.KeyDown},
+		{context: context, name: actionTop, description: "Top", keys: []string{"Home"}, native: tcell.KeyHome},
+		{context: context, name: actionBottom, description: "Bottom", keys: []string{"End"}, native: tcell.KeyEnd},
+		{context: context, name: actionPageUp, description: "Page up", keys: []string{"PgUp"}, native: tcell.KeyPgUp},
+		{context: context, name: actionPageDown, description: "Page down", keys: []string{"PgDn"}, native: tcell.KeyPgDn},
+	}
+}
+
+func concatActions(lists ...[]action) []action {
+	var all []action
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+
+	return all
+}
+
+// navigation tells whether the action moves through the rows or shows and
+// hides them, these are only listed in the help.
+func (a action) navigation() bool {
+	switch a.name {
+	case actionUp, actionDown, actionTop, actionBottom, actionPageUp, actionPageDown, actionExpand, actionCollapse:
+		return true
+	}
+
+	return false
+}
+
+// fullName identifies the action in the configuration, like
+// "results.download".
+func (a action) fullName() string {
+	return a.context + "." + a.name
+}
+
+// keyName names the key of the event the way the bindings do.
+func keyName(event *tcell.EventKey) string {
+	name := ""
+
+	if event.Key() == tcell.KeyRune {
+		name = string(event.Rune())
+		if event.Rune() == ' ' {
+			name = "Space"
+		}
+	} else if keyName, ok := tcell.KeyNames[event.Key()]; ok {
+		name = keyName
+	} else {
+		return ""
+	}
+
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		name = "Alt-" + name
+	}
+
+	return name
+}
+
+// Keys returns the keys bound to an action.
+func (ui *UI) Keys(a action) []string {
+	if keys, ok := ui.keymap[a.fullName()]; ok {
+		return keys
+	}
+
+	return a.keys
+}
+
+// Action returns the name of the action of the context bound to the key of
+// the event, and the event the widget should handle: the event itself, the
+// native key of the action, or nil when the native key was bound to another
+// key.
+func (ui *UI) Action(context string, event *tcell.EventKey) (string, *tcell.EventKey) {
+	name := keyName(event)
+	if name == "" {
+		return "", event
+	}
+
+	for _, a := range actions {
+		if a.context != context {
+			continue
+		}
+
+		for _, key := range ui.Keys(a) {
+			if key != name {
+				continue
+			}
+
+			if a.native != 0 {
+				return a.name, tcell.NewEventKey(a.native, 0, tcell.ModNone)
+			}
+
+			return a.name, event
+		}
+	}
+
+	for _, a := range actions {
+		if a.context == context && a.native != 0 && a.native == event.Key() {
+			return "", nil
+		}
+	}
+
+	// The tables and lists handle some characters by themselves, like j and k
+	// moving through the rows, only the bound characters do something
+	if event.Key() == tcell.KeyRune && navigable(context) {
+		return "", nil
+	}
+
+	return "", event
+}
+
+// navigable tells whether the widgets of the context move through rows.
+func navigable(context string) bool {
+	for _, a := range actions {
+		if a.context == context && a.name == actionUp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// focusBinds tells whether the key is bound in the context of the focused
+// widget, which takes precedence over the global bindings.
+func (ui *UI) focusBinds(event *tcell.EventKey) bool {
+	action, _ := ui.Action(ui.focusContext, event)
+	return action != ""
+}
+
+// typing tells whether the event is a character typed in a text field, which
+// is never a shortcut.
+func (ui *UI) typing(event *tcell.EventKey) bool {
+	_, ok := ui.app.GetFocus().(*tview.InputField)
+	return ok && event.Key() == tcell.KeyRune && event.Modifiers()&tcell.ModAlt == 0
+}
+
+// KeyHints describes the bindings of a context, like "Enter: play, Esc: back".
+func (ui *UI) KeyHints(context string) string {
+	var hints []string
+	for _, a := range actions {
+		if a.context == context && len(ui.Keys(a)) > 0 {
+			hints = append(hints, fmt.Sprintf("%s: %s", ui.Keys(a)[0], strings.ToLower(a.description)))
+		}
+	}
+
+	return tview.Escape(strings.Join(hints, ", "))
+}
+
+// focusable is implemented by the widgets, through their box.
+type focusable interface {
+	SetFocusFunc(callback func()) *tview.Box
+}
+
+// SetFocusContext shows the bindings of the context in the shortcut bar when
+// the widget gets the focus.
+func (ui *UI) SetFocusContext(widget focusable, context string) {
+	widget.SetFocusFunc(func() {
+		ui.UpdateShortcuts(context)
+	})
+}
+
+// UpdateShortcuts shows the bindings of the context followed by the global
+// ones in the shortcut bar.
+func (ui *UI) UpdateShortcuts(context string) {
+	if context == ui.focusContext {
+		return
+	}
+
+	ui.focusContext = context
+	ui.shortcuts.Clear()
+
+	for _, a := range actions {
+		if a.context == context && len(ui.Keys(a)) > 0 && !a.navigation() {
+			ui.AddShortcut(ui.Keys(a)[0], a.description)
+		}
+	}
+
+	for _, a := range actions {
+		if a.context == contextGlobal && len(ui.Keys(a)) > 0 {
+			ui.AddShortcut(ui.Keys(a)[0], a.description)
+		}
+	}
+}
+
+// ShowHelp lists every action and its keys.
+func (ui *UI) ShowHelp() {
+	help := tview.NewTable()
+	help.
+		SetBorder(true).
+		SetTitle(" Help (Esc: back) ").
+		SetBackgroundColor(tcell.ColorReset)
+
+	contexts := []string{contextGlobal, contextResults, contextForm, contextFilter, contextSearches, contextContinue, contextLibrary, contextDownloads}
+
+	row := 0
+	for _, context := range contexts {
+		if row > 0 {
+			row++
+		}
+
+		help.SetCell(row, 0, tview.NewTableCell(contextTitles[context]).
+			SetTextColor(ui.theme.Accent).
+			SetAttributes(tcell.AttrBold))
+		row++
+
+		for _, a := range actions {
+			if a.context != context {
+				continue
+			}
+
+			help.SetCell(row, 0, tview.NewTableCell("  "+tview.Escape(strings.Join(ui.Keys(a), ", "))).SetTextColor(ui.theme.Id))
+			help.SetCell(row, 1, tview.NewTableCell(a.description).SetExpansion(1))
+			help.SetCell(row, 2, tview.NewTableCell(a.fullName()).SetTextColor(ui.theme.Muted))
+			row++
+		}
+	}
+
+	focus := ui.app.GetFocus()
+
+	help.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if action, _ := ui.Action(contextGlobal, event); event.Key() == tcell.KeyEscape || action == actionHelp {
+			ui.pages.RemovePage("help")
+			ui.app.SetFocus(focus)
+			return nil
+		}
+
+		return event
+	})
+
+	ui.pages.AddPage("help", modal(help, 80, row+2), true, true)
+	ui.app.SetFocus(help)
+}
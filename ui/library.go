@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gdamore/tcell/v2"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/quantumsheep/nyaa-cli/utils"
+	"github.com/rivo/tview"
+)
+
+func (ui *UI) GenerateLibraryPage() {
+	ui.libraryList = tview.NewList().
+		ShowSecondaryText(true)
+
+	ui.libraryList.
+		SetBorder(true).
+		SetTitle(" Library (" + ui.KeyHints(contextLibrary) + ") ").
+		SetBackgroundColor(tcell.ColorReset)
+
+	ui.libraryList.SetDoneFunc(func() {
+		ui.pages.SwitchToPage("search")
+		ui.app.SetFocus(ui.table)
+	})
+
+	ui.libraryList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action, event := ui.Action(contextLibrary, event)
+		if action != actionRemove {
+			return event
+		}
+
+		index := ui.libraryList.GetCurrentItem()
+		if index >= len(ui.library.Items) {
+			return nil
+		}
+
+		confirm := tview.NewModal().
+			SetText(fmt.Sprintf("Delete %s?", tview.Escape(ui.library.Items[index].Path))).
+			AddButtons([]string{"Delete", "Cancel"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				ui.pages.RemovePage("library-delete")
+
+				if buttonLabel == "Delete" {
+					if err := ui.library.Remove(index); err != nil {
+						ui.Fatal(err)
+					}
+
+					ui.ShowLibrary()
+					ui.libraryList.SetCurrentItem(index)
+				}
+
+				ui.app.SetFocus(ui.libraryList)
+			})
+
+		ui.pages.AddPage("library-delete", confirm, true, true)
+
+		return nil
+	})
+
+	ui.pages.AddPage("library", ui.libraryList, true, false)
+}
+
+// ShowLibrary indexes the library directory and lists the videos. Without a
+// library directory, only the videos downloaded by nyaa-cli are listed.
+func (ui *UI) ShowLibrary() {
+	if ui.options.LibraryDirectory == "" {
+		if err := ui.library.Prune(); err != nil {
+			ui.Fatal(err)
+		}
+	} else if err := ui.library.Scan(ui.options.LibraryDirectory); err != nil {
+		ui.Fatal(err)
+	}
+
+	ui.libraryList.Clear()
+
+	for _, item := range ui.library.Items {
+		item := item
+
+		description := fmt.Sprintf("%s  %s", humanize.Bytes(uint64(item.Size)), item.Path)
+		ui.libraryList.AddItem(tview.Escape(item.Label()), tview.Escape(description), 0, func() {
+			ui.PlayLocal(item)
+		})
+	}
+
+	ui.pages.SwitchToPage("library")
+}
+
+// PlayLocal runs the video player directly on a file of the library, without
+// going through the torrent engine.
+func (ui *UI) PlayLocal(item library.Item) {
+	ui.app.Suspend(func() {
+		err := utils.RunVideoPlayer(utils.VideoPlayerConfig{
+			VideoPlayer: ui.options.VideoPlayer,
+			Url:         item.Path,
+			Name:        filepath.Base(item.Path),
+			OnTop:       true,
+			Fullscreen:  ui.options.Fullscreen,
+		})
+		if err != nil {
+			ui.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quantumsheep/nyaa-cli/engine"
+	"github.com/quantumsheep/nyaa-cli/history"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/quantumsheep/nyaa-cli/subtitles"
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+// Maximum time spent fetching a subtitle file before starting the player
+const subtitleTimeout = 30 * time.Second
+
+func (ui *UI) EnsureEngine() error {
+	if ui.engine != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(ui.options.DataDirectory, os.ModePerm); err != nil {
+		return err
+	}
+
+	var err error
+	ui.engine, err = engine.NewCacheEngine(ui.options.DataDirectory)
+	return err
+}
+
+// Play streams a file of the torrent to the video player and records it in
+// the watch history. The index is -1 for single file torrents. When the path
+// of the file isn't empty, the file is looked up by path in the metainfo
+// instead of by index.
+func (ui *UI) Play(guid string, link string, index int, path string) {
+	if err := ui.EnsureEngine(); err != nil {
+		ui.Notify(err.Error())
+		return
+	}
+
+	entry := history.WatchEntry{
+		GUID:     guid,
+		Link:     link,
+		FilePath: path,
+	}
+
+	ui.app.Suspend(func() {
+		if err := ui.engine.SetTorrentFromPath(link); err != nil {
+			ui.Fatal(err)
+		}
+		defer ui.engine.DropCurrentTorrent()
+
+		if path != "" {
+			var err error
+			index, err = ui.engine.FileIndex(path)
+			if err != nil {
+				ui.Fatal(err)
+			}
+		}
+		entry.FileIndex = index
+
+		startPosition := 0.0
+		if previous, ok := ui.watched.Find(guid, index); ok && !isFinished(previous) {
+			startPosition = previous.Position
+		}
+
+		// Make room for the played file, never evicting its torrent
+		if err := ui.cache.Evict(ui.engine.InfoHash(), uint64(ui.engine.Length(index))); err != nil {
+			ui.Fatal(err)
+		}
+		defer func() {
+			if err := ui.cache.Touch(ui.engine.InfoHash()); err != nil {
+				ui.Fatal(err)
+			}
+		}()
+
+		port := strconv.Itoa(ui.options.Port)
+		url := fmt.Sprintf("http://localhost:%s", port)
+
+		if index > -1 {
+			url += fmt.Sprintf("/%d", index)
+		}
+
+		wg := sync.WaitGroup{}
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+
+			if err := ui.engine.RunServer(port, 0); err != nil {
+				ui.Fatal(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			ui.engine.RunStatusLoop(index)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			entry.Name = ui.engine.GetFileName(index)
+
+			subtitlesDir, err := os.MkdirTemp("", "nyaa-cli-subtitles")
+			if err != nil {
+				ui.Fatal(err)
+			}
+			defer os.RemoveAll(subtitlesDir)
+
+			err = utils.RunVideoPlayer(utils.VideoPlayerConfig{
+				VideoPlayer:   ui.options.VideoPlayer,
+				Url:           url,
+				Name:          entry.Name,
+				OnTop:         true,
+				Fullscreen:    ui.options.Fullscreen,
+				Subtitles:     ui.Subtitles(index, subtitlesDir),
+				StartPosition: startPosition,
+				OnPosition: func(position float64, duration float64) {
+					entry.Position = position
+					entry.Duration = duration
+					entry.WatchedAt = time.Now()
+
+					// Saved while playing so the position survives a crash
+					ui.watched.Record(entry)
+					if err := ui.watched.Save(); err != nil {
+						ui.Fatal(err)
+					}
+				},
+			})
+			if err != nil {
+				ui.Fatal(err)
+			}
+
+			ui.engine.StopStatusLoop()
+
+			// Indexed before the deferred drop closes the torrent
+			ui.IndexFinishedTorrent(guid)
+
+			err = ui.engine.StopServer()
+			if err != nil {
+				ui.Fatal(err)
+			}
+		}()
+
+		wg.Wait()
+	})
+
+	entry.WatchedAt = time.Now()
+	ui.watched.Record(entry)
+
+	if err := ui.watched.Save(); err != nil {
+		ui.Fatal(err)
+	}
+}
+
+// isFinished tells if the entry was watched until its last minute, in which
+// case the playback restarts from the beginning.
+func isFinished(entry history.WatchEntry) bool {
+	return entry.Duration > 0 && entry.Duration-entry.Position < 60
+}
+
+// Subtitles returns the subtitle files going with the played file, the ones
+// shipped in the torrent are extracted into the directory.
+func (ui *UI) Subtitles(index int, directory string) []string {
+	var files []string
+
+	video := ui.engine.GetFileName(index)
+
+	if index > -1 {
+		paths := ui.engine.FilePaths()
+		video = paths[index]
+
+		for _, i := range subtitles.Match(paths[index], paths) {
+			ctx, cancel := context.WithTimeout(context.Background(), subtitleTimeout)
+			file, err := ui.engine.ExtractFile(ctx, i, directory)
+			cancel()
+
+			// A missing subtitle shouldn't prevent watching the video
+			if err == nil {
+				files = append(files, file)
+			}
+		}
+	}
+
+	if ui.options.SubtitlesDirectory != "" {
+		local, err := subtitles.FindInDirectory(ui.options.SubtitlesDirectory, video)
+		if err == nil {
+			files = append(files, local...)
+		}
+	}
+
+	return files
+}
+
+// IndexFinishedTorrent adds the videos of the current torrent to the library
+// once it's fully downloaded.
+func (ui *UI) IndexFinishedTorrent(guid string) {
+	if !ui.engine.IsComplete() {
+		return
+	}
+
+	for i, path := range ui.engine.FilePaths() {
+		if !library.IsVideo(path) {
+			continue
+		}
+
+		localPath, err := filepath.Abs(ui.engine.LocalPath(i))
+		if err != nil {
+			ui.Fatal(err)
+		}
+
+		info, err := os.Stat(localPath)
+		if err != nil {
+			continue
+		}
+
+		item := library.NewItem(localPath, info.Size())
+		item.GUID = guid
+		item.InfoHash = ui.engine.InfoHash()
+		item.Streamed = true
+
+		ui.library.Add(item)
+	}
+
+	if err := ui.library.Save(); err != nil {
+		ui.Fatal(err)
+	}
+}
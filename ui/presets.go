@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Bindings replacing the default ones, by name of keymap
+var keymapPresets = map[string]map[string][]string{
+	"default": {},
+	"vim": mergeBindings(
+		map[string][]string{
+			"global.quit":      {"Ctrl-C", "q"},
+			"results.select":   {"Space", "v"},
+			"results.expand":   {"l", "Right"},
+			"results.collapse": {"h", "Left"},
+			"results.back":     {"Esc", "Tab", "Backtab"},
+			"searches.back":    {"Esc", "q"},
+			"searches.remove":  {"Delete", "x"},
+			"continue.back":    {"Esc", "q"},
+			"continue.remove":  {"Delete", "x"},
+			"library.back":     {"Esc", "q"},
+			"library.remove":   {"Delete", "x"},
+			"downloads.back":   {"Esc", "q"},
+			"downloads.cancel": {"Delete", "x"},
+		},
+		navigationBindings(map[string][]string{
+			actionUp:       {"k", "Up"},
+			actionDown:     {"j", "Down"},
+			actionTop:      {"g", "Home"},
+			actionBottom:   {"G", "End"},
+			actionPageUp:   {"Ctrl-B", "PgUp"},
+			actionPageDown: {"Ctrl-F", "PgDn"},
+		}),
+	),
+	"emacs": mergeBindings(
+		map[string][]string{
+			"form.back":        {"Ctrl-G", "Esc"},
+			"results.back":     {"Ctrl-G", "Esc", "Tab", "Backtab"},
+			"results.filter":   {"Ctrl-S", "/"},
+			"results.expand":   {"Ctrl-F", "Right"},
+			"results.collapse": {"Ctrl-B", "Left"},
+			"filter.clear":     {"Ctrl-G", "Esc"},
+			"searches.back":    {"Ctrl-G", "Esc"},
+			"continue.back":    {"Ctrl-G", "Esc"},
+			"library.back":     {"Ctrl-G", "Esc"},
+			"downloads.back":   {"Ctrl-G", "Esc"},
+		},
+		navigationBindings(map[string][]string{
+			actionUp:       {"Ctrl-P", "Up"},
+			actionDown:     {"Ctrl-N", "Down"},
+			actionTop:      {"Alt-<", "Home"},
+			actionBottom:   {"Alt->", "End"},
+			actionPageUp:   {"Alt-v", "PgUp"},
+			actionPageDown: {"Ctrl-V", "PgDn"},
+		}),
+	),
+}
+
+// navigationBindings binds the navigation actions of every table and list.
+func navigationBindings(keys map[string][]string) map[string][]string {
+	bindings := make(map[string][]string)
+	for _, context := range []string{contextResults, contextSearches, contextContinue, contextLibrary, contextDownloads} {
+		for name, k := range keys {
+			bindings[context+"."+name] = k
+		}
+	}
+
+	return bindings
+}
+
+func mergeBindings(maps ...map[string][]string) map[string][]string {
+	bindings := make(map[string][]string)
+	for _, m := range maps {
+		for name, keys := range m {
+			bindings[name] = keys
+		}
+	}
+
+	return bindings
+}
+
+// BuildKeymap returns the bindings of the preset overridden by the user ones.
+// An empty list of keys unbinds an action.
+func BuildKeymap(preset string, keys map[string][]string) (map[string][]string, error) {
+	bindings, ok := keymapPresets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown keymap %q, available options: default, vim, emacs", preset)
+	}
+
+	for name, k := range keys {
+		if !isAction(name) {
+			return nil, fmt.Errorf("unknown action %q in the key bindings", name)
+		}
+
+		for _, key := range k {
+			if !isKeyName(key) {
+				return nil, fmt.Errorf("unknown key %q bound to %s", key, name)
+			}
+		}
+	}
+
+	keymap := mergeBindings(bindings, keys)
+	if err := checkConflicts(keymap); err != nil {
+		return nil, err
+	}
+
+	return keymap, nil
+}
+
+// checkConflicts fails when a key is bound to two actions of the same context,
+// as only the first one would run.
+func checkConflicts(keymap map[string][]string) error {
+	// Full name of the action bound to each key, by context
+	bound := make(map[string]map[string]string)
+
+	for _, a := range actions {
+		keys, ok := keymap[a.fullName()]
+		if !ok {
+			keys = a.keys
+		}
+
+		if bound[a.context] == nil {
+			bound[a.context] = make(map[string]string)
+		}
+
+		for _, key := range keys {
+			if other, ok := bound[a.context][key]; ok && other != a.fullName() {
+				return fmt.Errorf("key %q is bound to both %s and %s", key, other, a.fullName())
+			}
+
+			bound[a.context][key] = a.fullName()
+		}
+	}
+
+	return nil
+}
+
+func isAction(name string) bool {
+	for _, a := range actions {
+		if a.fullName() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isKeyName tells whether the key is named like keyName names them.
+func isKeyName(key string) bool {
+	key = strings.TrimPrefix(key, "Alt-")
+
+	if key == "Space" || len([]rune(key)) == 1 {
+		return true
+	}
+
+	for _, name := range tcell.KeyNames {
+		if name == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	for name := range keymapPresets {
+		if _, err := BuildKeymap(name, nil); err != nil {
+			t.Errorf("preset %s: %v", name, err)
+		}
+	}
+}
+
+func TestBuildKeymapConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		preset  string
+		keys    map[string][]string
+		wantErr string
+	}{
+		{
+			name:    "default key of another action",
+			preset:  "default",
+			keys:    map[string][]string{"results.download": {"s"}},
+			wantErr: `key "s" is bound to both results.download and results.sort`,
+		},
+		{
+			name:    "preset key of another action",
+			preset:  "vim",
+			keys:    map[string][]string{"results.download": {"j"}},
+			wantErr: `key "j" is bound to both results.download and results.down`,
+		},
+		{
+			name:    "two user bindings",
+			preset:  "default",
+			keys:    map[string][]string{"library.open": {"o"}, "library.remove": {"o"}},
+			wantErr: `key "o" is bound to both library.open and library.remove`,
+		},
+		{
+			name:   "moved key",
+			preset: "default",
+			keys:   map[string][]string{"results.download": {"s"}, "results.sort": {"o"}},
+		},
+		{
+			name:   "same key in other contexts",
+			preset: "default",
+			keys:   map[string][]string{"results.download": {"x"}, "library.remove": {"x"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := BuildKeymap(test.preset, test.keys)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestActionRunes(t *testing.T) {
+	tests := []struct {
+		preset  string
+		context string
+		rune    rune
+		action  string
+		// Key of the event the widget handles, zero when it's swallowed
+		key tcell.Key
+	}{
+		{preset: "default", context: contextResults, rune: 'j'},
+		{preset: "default", context: contextLibrary, rune: ' '},
+		{preset: "default", context: contextResults, rune: 'd', action: actionDownload, key: tcell.KeyRune},
+		{preset: "vim", context: contextResults, rune: 'j', action: actionDown, key: tcell.KeyDown},
+		{preset: "vim", context: contextDownloads, rune: 'G', action: actionBottom, key: tcell.KeyEnd},
+		{preset: "default", context: contextFilter, rune: 'j', key: tcell.KeyRune},
+	}
+
+	for _, test := range tests {
+		keymap, err := BuildKeymap(test.preset, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ui := &UI{keymap: keymap}
+		action, event := ui.Action(test.context, tcell.NewEventKey(tcell.KeyRune, test.rune, tcell.ModNone))
+
+		key := tcell.Key(0)
+		if event != nil {
+			key = event.Key()
+		}
+
+		if action != test.action || key != test.key {
+			t.Errorf("%s %s %q: got action %q and key %v, want %q and %v", test.preset, test.context, test.rune, action, key, test.action, test.key)
+		}
+	}
+}
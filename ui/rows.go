@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"github.com/quantumsheep/nyaa-cli/parser"
+	"github.com/rivo/tview"
+)
+
+// tableRow is what a row of the results table shows: a torrent, or one of its
+// files when index isn't -1. The name cell of each row references it.
+type tableRow struct {
+	torrent *Torrent
+	index   int
+}
+
+// Row returns what the row of the table shows, nil for the header.
+func (ui *UI) Row(row int) *tableRow {
+	cell := ui.getCell(row, columnName)
+	if cell == nil {
+		return nil
+	}
+
+	r, _ := cell.GetReference().(*tableRow)
+	return r
+}
+
+// torrentRow returns the row of the torrent in the table, or -1 when it isn't
+// visible.
+func (ui *UI) torrentRow(torrent *Torrent) int {
+	for row := 0; row < ui.table.GetRowCount(); row++ {
+		if r := ui.Row(row); r != nil && r.torrent == torrent && r.index == -1 {
+			return row
+		}
+	}
+
+	return -1
+}
+
+// SetFileRow renders the file of the torrent on the given row.
+func (ui *UI) SetFileRow(row int, torrent *Torrent, index int) {
+	file := torrent.files[index]
+
+	ui.setCell(row, columnId, ui.GenerateCell("", 8, 0, ui.theme.Text).SetAlign(tview.AlignLeft))
+	ui.setCell(row, columnSaved, ui.GenerateCell("│", 4, 0, ui.theme.Muted))
+	ui.setCell(row, columnWatched, ui.GenerateWatchedFileCell(torrent.GUID, file.path))
+	ui.setCell(row, columnSize, ui.GenerateCell(file.size, 10, 0, ui.theme.Size))
+	ui.setCell(row, columnDate, ui.GenerateCell("", 17, 0, ui.theme.Text))
+	ui.setCell(row, columnSeeders, ui.GenerateCell("", 6, 0, ui.theme.Text))
+	ui.setCell(row, columnLeechers, ui.GenerateCell("", 6, 0, ui.theme.Text))
+	ui.setCell(row, columnDownloads, ui.GenerateCell("", 7, 0, ui.theme.Text))
+	ui.setCell(row, columnTrusted, ui.GenerateCell("", 2, 0, ui.theme.Text))
+	ui.setCell(row, columnRemake, ui.GenerateCell("", 2, 0, ui.theme.Text))
+	ui.setCell(row, columnCategory, ui.GenerateCell("", 0, 0, ui.theme.Text))
+	ui.setCell(row, columnResolution, ui.GenerateCell("", 6, 0, ui.theme.Text))
+	ui.setCell(row, columnEpisode, ui.GenerateCell(parser.Parse(file.name).EpisodeLabel(), 8, 0, ui.theme.Episode))
+	ui.setCell(row, columnName, ui.GenerateNameCell("", file.name, ui.theme.File).SetReference(&tableRow{torrent: torrent, index: index}))
+	ui.SetRowSelected(row, torrent.selectedFiles[index])
+}
+
+// ListFiles fetches the files of the torrent the first time, the lists are
+// kept for the next searches returning the same torrent.
+func (ui *UI) ListFiles(torrent *Torrent) error {
+	if torrent.files != nil {
+		return nil
+	}
+
+	if files, ok := ui.fileLists[torrent.GUID]; ok {
+		torrent.files = files
+		return nil
+	}
+
+	files, err := torrentFiles(torrent)
+	if err != nil {
+		return err
+	}
+
+	if ui.fileLists == nil {
+		ui.fileLists = make(map[string][]*torrentFile)
+	}
+
+	ui.fileLists[torrent.GUID] = files
+	torrent.files = files
+	return nil
+}
+
+// Expand lists the files of the torrent of the row below it, it returns false
+// when the torrent has a single file.
+func (ui *UI) Expand(row int) bool {
+	r := ui.Row(row)
+	if r == nil {
+		return false
+	}
+
+	torrent := r.torrent
+	if torrent.expanded {
+		return true
+	}
+
+	if err := ui.ListFiles(torrent); err != nil {
+		ui.Fatal(err)
+	}
+
+	if len(torrent.files) <= 1 {
+		return false
+	}
+
+	row = ui.torrentRow(torrent)
+	for i := range torrent.files {
+		ui.table.InsertRow(row + 1 + i)
+		ui.SetFileRow(row+1+i, torrent, i)
+	}
+
+	torrent.expanded = true
+	return true
+}
+
+// Collapse hides the files of the torrent of the row and selects the row of
+// the torrent.
+func (ui *UI) Collapse(row int) {
+	r := ui.Row(row)
+	if r == nil || !r.torrent.expanded {
+		return
+	}
+
+	row = ui.torrentRow(r.torrent)
+	for next := ui.Row(row + 1); next != nil && next.torrent == r.torrent; next = ui.Row(row + 1) {
+		ui.table.RemoveRow(row + 1)
+	}
+
+	r.torrent.expanded = false
+	ui.table.Select(row, 0)
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/quantumsheep/nyaa-cli/history"
+	"github.com/rivo/tview"
+)
+
+func (ui *UI) GenerateSearchesPage() {
+	ui.searches = tview.NewList().
+		ShowSecondaryText(true)
+
+	ui.searches.
+		SetBorder(true).
+		SetTitle(" Searches (" + ui.KeyHints(contextSearches) + ") ").
+		SetBackgroundColor(tcell.ColorReset)
+
+	ui.searches.SetDoneFunc(func() {
+		ui.pages.SwitchToPage("search")
+		ui.app.SetFocus(ui.table)
+	})
+
+	ui.searches.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		action, event := ui.Action(contextSearches, event)
+		if action != actionRemove {
+			return event
+		}
+
+		index := ui.searches.GetCurrentItem()
+		if index < len(ui.history.Saved) {
+			ui.history.RemoveSaved(ui.history.Saved[index].Name)
+		} else if index-len(ui.history.Saved) < len(ui.history.Queries) {
+			ui.history.RemoveQuery(ui.history.Queries[index-len(ui.history.Saved)])
+		}
+
+		if err := ui.history.Save(); err != nil {
+			ui.Fatal(err)
+		}
+
+		ui.ShowSearches()
+		ui.searches.SetCurrentItem(index)
+
+		return nil
+	})
+
+	ui.pages.AddPage("searches", ui.searches, true, false)
+}
+
+// ShowSearches lists the saved searches followed by the recent queries.
+func (ui *UI) ShowSearches() {
+	ui.searches.Clear()
+
+	for _, saved := range ui.history.Saved {
+		saved := saved
+
+		description := fmt.Sprintf("%q in %s, %s, sorted by %s %s", saved.Query, saved.Category, saved.Filter, saved.SortBy, saved.OrderBy)
+		ui.searches.AddItem("★ "+tview.Escape(saved.Name), tview.Escape(description), 0, func() {
+			ui.ApplySearch(saved)
+			ui.pages.SwitchToPage("search")
+			ui.RunSearch()
+		})
+	}
+
+	for _, query := range ui.history.Queries {
+		query := query
+
+		ui.searches.AddItem(tview.Escape(query), "", 0, func() {
+			ui.searchForm.GetFormItemByLabel("Query").(*tview.InputField).SetText(query)
+			ui.pages.SwitchToPage("search")
+			ui.RunSearch()
+		})
+	}
+
+	ui.pages.SwitchToPage("searches")
+}
+
+// ShowSaveSearch asks for a name and saves the current search form values
+// under it.
+func (ui *UI) ShowSaveSearch() {
+	name := ""
+
+	dismiss := func() {
+		ui.pages.RemovePage("save-search")
+		ui.app.SetFocus(ui.table)
+	}
+
+	form := tview.NewForm().
+		AddInputField("Name", "", 32, nil, func(text string) {
+			name = strings.TrimSpace(text)
+		}).
+		AddButton("Save", func() {
+			if name == "" {
+				return
+			}
+
+			opts := ui.SearchOptions()
+			ui.history.SaveSearch(history.SavedSearch{
+				Name:     name,
+				Query:    opts.Query,
+				Category: opts.Category,
+				Filter:   opts.Filter,
+				SortBy:   opts.SortBy,
+				OrderBy:  opts.OrderBy,
+			})
+
+			if err := ui.history.Save(); err != nil {
+				ui.Fatal(err)
+			}
+
+			dismiss()
+		}).
+		AddButton("Cancel", dismiss)
+
+	form.SetCancelFunc(dismiss)
+	form.
+		SetBorder(true).
+		SetTitle(" Save search ")
+
+	ui.pages.AddPage("save-search", modal(form, 50, 7), true, true)
+}
+
+// ApplySearch fills the search form with the values of a saved search.
+func (ui *UI) ApplySearch(saved history.SavedSearch) {
+	ui.searchForm.GetFormItemByLabel("Query").(*tview.InputField).SetText(saved.Query)
+	ui.searchForm.GetFormItemByLabel("Category").(*tview.DropDown).SetCurrentOption(optionIndex(ui.Categories(), saved.Category))
+	ui.searchForm.GetFormItemByLabel("Filter").(*tview.DropDown).SetCurrentOption(optionIndex(filterOptions, saved.Filter))
+	ui.searchForm.GetFormItemByLabel("Sort By").(*tview.DropDown).SetCurrentOption(optionIndex(sortOptions, saved.SortBy))
+	ui.searchForm.GetFormItemByLabel("Order By").(*tview.DropDown).SetCurrentOption(optionIndex(orderOptions, saved.OrderBy))
+}
+
+func modal(p tview.Primitive, width int, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/quantumsheep/nyaa-cli/utils"
+	"github.com/rivo/tview"
+)
+
+// ToggleSelection selects or unselects the torrent of the row, or ticks the
+// file of the row for download, and moves to the next row.
+func (ui *UI) ToggleSelection(row int) {
+	r := ui.Row(row)
+	if r == nil {
+		return
+	}
+
+	torrent, index := r.torrent, r.index
+
+	if index == -1 {
+		torrent.isSelected = !torrent.isSelected
+		ui.SetRowSelected(row, torrent.isSelected)
+	} else {
+		if torrent.selectedFiles == nil {
+			torrent.selectedFiles = make(map[int]bool)
+		}
+
+		if torrent.selectedFiles[index] {
+			delete(torrent.selectedFiles, index)
+		} else {
+			torrent.selectedFiles[index] = true
+		}
+
+		ui.SetRowSelected(row, torrent.selectedFiles[index])
+	}
+
+	if row+1 < ui.table.GetRowCount() {
+		ui.table.Select(row+1, 0)
+	}
+}
+
+// SelectAll selects every visible torrent, or unselects them when they are
+// all selected already.
+func (ui *UI) SelectAll() {
+	var rows []int
+	allSelected := true
+
+	for row := 0; row < ui.table.GetRowCount(); row++ {
+		if r := ui.Row(row); r != nil && r.index == -1 {
+			rows = append(rows, row)
+			allSelected = allSelected && r.torrent.isSelected
+		}
+	}
+
+	for _, row := range rows {
+		ui.Row(row).torrent.isSelected = !allSelected
+		ui.SetRowSelected(row, !allSelected)
+	}
+}
+
+// Selection returns the selected torrents and the torrents with ticked files
+// in the order of the table. The torrents hidden by the filters or in a
+// collapsed group are left out.
+func (ui *UI) Selection() []*Torrent {
+	var torrents []*Torrent
+	for row := 0; row < ui.table.GetRowCount(); row++ {
+		r := ui.Row(row)
+		if r != nil && r.index == -1 && (r.torrent.isSelected || len(r.torrent.selectedFiles) > 0) {
+			torrents = append(torrents, r.torrent)
+		}
+	}
+
+	return torrents
+}
+
+// Targets returns the torrents an action applies to: the selected torrents,
+// or the torrent of the row when none is selected.
+func (ui *UI) Targets(row int) []*Torrent {
+	if selection := ui.Selection(); len(selection) > 0 {
+		return selection
+	}
+
+	if r := ui.Row(row); r != nil {
+		return []*Torrent{r.torrent}
+	}
+
+	return nil
+}
+
+// SelectedFiles returns the paths of the ticked files in the order of the
+// list, nil when the whole torrent is downloaded.
+func (t *Torrent) SelectedFiles() []string {
+	var indices []int
+	for index := range t.selectedFiles {
+		indices = append(indices, index)
+	}
+
+	sort.Ints(indices)
+
+	var paths []string
+	for _, index := range indices {
+		paths = append(paths, t.files[index].path)
+	}
+
+	return paths
+}
+
+// SetRowSelected highlights the cells of a selected row.
+func (ui *UI) SetRowSelected(row int, selected bool) {
+	if row < 0 {
+		return
+	}
+
+	background := tcell.ColorReset
+	attributes := tcell.AttrNone
+	if selected {
+		background = ui.theme.Selected
+		attributes = ui.theme.SelectedAttributes
+	}
+
+	for column := 0; column < ui.table.GetColumnCount(); column++ {
+		if cell := ui.table.GetCell(row, column); cell != nil {
+			cell.SetBackgroundColor(background)
+			cell.SetAttributes(attributes)
+		}
+	}
+}
+
+// SaveTorrentFile downloads the .torrent file into the output directory.
+func (ui *UI) SaveTorrentFile(torrent *Torrent) error {
+	directory, err := filepath.Abs(ui.options.OutputDirectory)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return err
+	}
+
+	_, err = utils.Download(torrent.Link, filepath.Join(directory, utils.SafeFilename(torrent.Name, ".torrent")))
+	if err != nil {
+		return err
+	}
+
+	torrent.isSaved = true
+
+	if row := ui.torrentRow(torrent); row >= 0 {
+		ui.setCell(row, columnSaved, ui.GenerateCell("●", 4, 0, ui.theme.Saved).SetAlign(tview.AlignRight))
+		ui.SetRowSelected(row, torrent.isSelected)
+	}
+
+	return nil
+}
+
+// SaveTorrentFiles downloads the .torrent files of the torrents. A failed
+// download doesn't prevent saving the next ones, the failures are notified.
+func (ui *UI) SaveTorrentFiles(torrents []*Torrent) {
+	var failures []string
+	for _, torrent := range torrents {
+		if err := ui.SaveTorrentFile(torrent); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", torrent.Name, err))
+		}
+	}
+
+	saved := len(torrents) - len(failures)
+
+	switch {
+	case len(torrents) == 1 && saved == 1:
+		ui.Notify("Saved " + utils.SafeFilename(torrents[0].Name, ".torrent"))
+	case len(torrents) == 1:
+		ui.Notify(failures[0])
+	case len(failures) > 0:
+		ui.Notify(fmt.Sprintf("Saved %d/%d .torrent files, %s", saved, len(torrents), strings.Join(failures, ", ")))
+	case saved > 1:
+		ui.Notify(fmt.Sprintf("Saved %d .torrent files", saved))
+	}
+}
+
+// CopyTorrents copies the magnet links or the infohashes of the torrents to
+// the clipboard, one per line. The magnet links are built in the background
+// as their .torrent files may have to be fetched.
+func (ui *UI) CopyTorrents(torrents []*Torrent, magnets bool) {
+	if len(torrents) == 0 {
+		return
+	}
+
+	if !magnets {
+		lines, skipped := infoHashLines(torrents)
+		ui.copyTorrentLines(lines, skipped, false)
+		return
+	}
+
+	go func() {
+		var lines []string
+		skipped := 0
+		for _, torrent := range torrents {
+			magnet, err := utils.Magnet(ui.options.Provider, torrent.Link, torrent.InfoHash, torrent.Name)
+			if err != nil {
+				skipped++
+				continue
+			}
+
+			lines = append(lines, magnet)
+		}
+
+		ui.app.QueueUpdateDraw(func() {
+			ui.copyTorrentLines(lines, skipped, true)
+		})
+	}()
+}
+
+// infoHashLines returns the infohashes of the torrents and the number of
+// torrents without one.
+func infoHashLines(torrents []*Torrent) ([]string, int) {
+	var lines []string
+	skipped := 0
+	for _, torrent := range torrents {
+		// Some feed items have no infohash, they can't be copied
+		if torrent.InfoHash == "" {
+			skipped++
+			continue
+		}
+
+		lines = append(lines, torrent.InfoHash)
+	}
+
+	return lines, skipped
+}
+
+// copyTorrentLines copies the magnet links or infohashes to the clipboard,
+// skipped being the number of torrents left out.
+func (ui *UI) copyTorrentLines(lines []string, skipped int, magnets bool) {
+	what := "infohash"
+	if magnets {
+		what = "magnet link"
+	}
+
+	if len(lines) == 0 {
+		ui.Notify("No " + what + " to copy")
+		return
+	}
+
+	if err := utils.CopyToClipboard(strings.Join(lines, "\n")); err != nil {
+		ui.Fatal(err)
+	}
+
+	message := "Copied " + what + " to the clipboard"
+	if len(lines) > 1 {
+		message = fmt.Sprintf("Copied %d %ss to the clipboard", len(lines), what)
+	}
+	if skipped > 0 && magnets {
+		message += fmt.Sprintf(", skipped %d without a .torrent file or valid infohash", skipped)
+	} else if skipped > 0 {
+		message += fmt.Sprintf(", skipped %d without an infohash", skipped)
+	}
+
+	ui.Notify(message)
+}
+
+// SendTorrents adds the torrents to the remote torrent client in the
+// background.
+func (ui *UI) SendTorrents(torrents []*Torrent) {
+	if len(torrents) == 0 {
+		return
+	}
+
+	ui.Notify(fmt.Sprintf("Sending %d torrents", len(torrents)))
+
+	go func() {
+		var err error
+		for _, torrent := range torrents {
+			if err = ui.SendToClient(torrent); err != nil {
+				err = fmt.Errorf("%s: %w", torrent.Name, err)
+				break
+			}
+		}
+
+		ui.app.QueueUpdateDraw(func() {
+			if err != nil {
+				ui.Notify(err.Error())
+			} else {
+				ui.Notify(fmt.Sprintf("Sent %d torrents", len(torrents)))
+			}
+		})
+	}()
+}
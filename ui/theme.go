@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Theme holds the colors of the interface.
+type Theme struct {
+	Text   tcell.Color
+	Muted  tcell.Color
+	Border tcell.Color
+	Title  tcell.Color
+	Header tcell.Color
+	// Background of the form fields
+	Field tcell.Color
+	// Section titles of the help
+	Accent tcell.Color
+	Error  tcell.Color
+
+	Id          tcell.Color
+	Saved       tcell.Color
+	Watched     tcell.Color
+	Size        tcell.Color
+	Date        tcell.Color
+	Seeders     tcell.Color
+	Leechers    tcell.Color
+	Remake      tcell.Color
+	Resolution  tcell.Color
+	Episode     tcell.Color
+	Alternative tcell.Color
+	File        tcell.Color
+
+	// Terms of the query highlighted in the names
+	Match           tcell.Color
+	MatchAttributes tcell.AttrMask
+
+	// Background and attributes of the selected rows
+	Selected           tcell.Color
+	SelectedAttributes tcell.AttrMask
+
+	ShortcutKey        tcell.Color
+	ShortcutLabel      tcell.Color
+	ShortcutBackground tcell.Color
+	ShortcutAttributes tcell.AttrMask
+}
+
+var themes = map[string]*Theme{
+	"dark": {
+		Text:               tcell.ColorWhite,
+		Muted:              tcell.ColorGray,
+		Border:             tcell.ColorWhite,
+		Title:              tcell.ColorWhite,
+		Header:             tcell.ColorWhite,
+		Field:              tcell.ColorBlue,
+		Accent:             tcell.ColorYellow,
+		Error:              tcell.ColorRed,
+		Id:                 tcell.ColorBlue,
+		Saved:              tcell.ColorGreen,
+		Watched:            tcell.ColorPurple,
+		Size:               tcell.ColorYellow,
+		Date:               tcell.ColorGray,
+		Seeders:            tcell.ColorGreen,
+		Leechers:           tcell.ColorRed,
+		Remake:             tcell.ColorRed,
+		Resolution:         tcell.ColorTeal,
+		Episode:            tcell.ColorFuchsia,
+		Alternative:        tcell.ColorDarkGray,
+		File:               tcell.ColorDimGray,
+		Match:              tcell.ColorYellow,
+		MatchAttributes:    tcell.AttrBold,
+		Selected:           tcell.ColorNavy,
+		ShortcutKey:        tcell.ColorWhite,
+		ShortcutLabel:      tcell.ColorBlack,
+		ShortcutBackground: tcell.ColorBlue,
+	},
+	"light": {
+		Text:               tcell.ColorBlack,
+		Muted:              tcell.ColorDimGray,
+		Border:             tcell.ColorBlack,
+		Title:              tcell.ColorBlack,
+		Header:             tcell.ColorBlack,
+		Field:              tcell.ColorLightGray,
+		Accent:             tcell.ColorNavy,
+		Error:              tcell.ColorRed,
+		Id:                 tcell.ColorNavy,
+		Saved:              tcell.ColorGreen,
+		Watched:            tcell.ColorPurple,
+		Size:               tcell.ColorOlive,
+		Date:               tcell.ColorDimGray,
+		Seeders:            tcell.ColorGreen,
+		Leechers:           tcell.ColorMaroon,
+		Remake:             tcell.ColorMaroon,
+		Resolution:         tcell.ColorTeal,
+		Episode:            tcell.ColorPurple,
+		Alternative:        tcell.ColorGray,
+		File:               tcell.ColorDimGray,
+		Match:              tcell.ColorMaroon,
+		MatchAttributes:    tcell.AttrBold,
+		Selected:           tcell.ColorLightSkyBlue,
+		ShortcutKey:        tcell.ColorBlack,
+		ShortcutLabel:      tcell.ColorWhite,
+		ShortcutBackground: tcell.ColorNavy,
+	},
+	"solarized": {
+		Text:               tcell.NewHexColor(0x839496),
+		Muted:              tcell.NewHexColor(0x586e75),
+		Border:             tcell.NewHexColor(0x586e75),
+		Title:              tcell.NewHexColor(0x93a1a1),
+		Header:             tcell.NewHexColor(0x93a1a1),
+		Field:              tcell.NewHexColor(0x073642),
+		Accent:             tcell.NewHexColor(0xb58900),
+		Error:              tcell.NewHexColor(0xdc322f),
+		Id:                 tcell.NewHexColor(0x268bd2),
+		Saved:              tcell.NewHexColor(0x859900),
+		Watched:            tcell.NewHexColor(0x6c71c4),
+		Size:               tcell.NewHexColor(0xb58900),
+		Date:               tcell.NewHexColor(0x586e75),
+		Seeders:            tcell.NewHexColor(0x859900),
+		Leechers:           tcell.NewHexColor(0xdc322f),
+		Remake:             tcell.NewHexColor(0xcb4b16),
+		Resolution:         tcell.NewHexColor(0x2aa198),
+		Episode:            tcell.NewHexColor(0xd33682),
+		Alternative:        tcell.NewHexColor(0x586e75),
+		File:               tcell.NewHexColor(0x657b83),
+		Match:              tcell.NewHexColor(0xcb4b16),
+		MatchAttributes:    tcell.AttrBold,
+		Selected:           tcell.NewHexColor(0x073642),
+		ShortcutKey:        tcell.NewHexColor(0x93a1a1),
+		ShortcutLabel:      tcell.NewHexColor(0x002b36),
+		ShortcutBackground: tcell.NewHexColor(0x268bd2),
+	},
+	"high-contrast": {
+		Text:               tcell.ColorWhite,
+		Muted:              tcell.ColorWhite,
+		Border:             tcell.ColorWhite,
+		Title:              tcell.ColorYellow,
+		Header:             tcell.ColorYellow,
+		Field:              tcell.ColorBlack,
+		Accent:             tcell.ColorYellow,
+		Error:              tcell.ColorRed,
+		Id:                 tcell.ColorAqua,
+		Saved:              tcell.ColorLime,
+		Watched:            tcell.ColorFuchsia,
+		Size:               tcell.ColorYellow,
+		Date:               tcell.ColorWhite,
+		Seeders:            tcell.ColorLime,
+		Leechers:           tcell.ColorRed,
+		Remake:             tcell.ColorRed,
+		Resolution:         tcell.ColorAqua,
+		Episode:            tcell.ColorFuchsia,
+		Alternative:        tcell.ColorSilver,
+		File:               tcell.ColorSilver,
+		Match:              tcell.ColorYellow,
+		MatchAttributes:    tcell.AttrBold,
+		Selected:           tcell.ColorDefault,
+		SelectedAttributes: tcell.AttrUnderline | tcell.AttrBold,
+		ShortcutKey:        tcell.ColorYellow,
+		ShortcutLabel:      tcell.ColorBlack,
+		ShortcutBackground: tcell.ColorWhite,
+	},
+}
+
+// noColorTheme uses the default colors of the terminal, see
+// https://no-color.org.
+var noColorTheme = &Theme{
+	Text:               tcell.ColorDefault,
+	Muted:              tcell.ColorDefault,
+	Border:             tcell.ColorDefault,
+	Title:              tcell.ColorDefault,
+	Header:             tcell.ColorDefault,
+	Field:              tcell.ColorDefault,
+	Accent:             tcell.ColorDefault,
+	Error:              tcell.ColorDefault,
+	Id:                 tcell.ColorDefault,
+	Saved:              tcell.ColorDefault,
+	Watched:            tcell.ColorDefault,
+	Size:               tcell.ColorDefault,
+	Date:               tcell.ColorDefault,
+	Seeders:            tcell.ColorDefault,
+	Leechers:           tcell.ColorDefault,
+	Remake:             tcell.ColorDefault,
+	Resolution:         tcell.ColorDefault,
+	Episode:            tcell.ColorDefault,
+	Alternative:        tcell.ColorDefault,
+	File:               tcell.ColorDefault,
+	Match:              tcell.ColorDefault,
+	MatchAttributes:    tcell.AttrBold | tcell.AttrUnderline,
+	Selected:           tcell.ColorDefault,
+	SelectedAttributes: tcell.AttrUnderline,
+	ShortcutKey:        tcell.ColorDefault,
+	ShortcutLabel:      tcell.ColorDefault,
+	ShortcutBackground: tcell.ColorDefault,
+	ShortcutAttributes: tcell.AttrReverse,
+}
+
+// LoadTheme returns the named theme, or the theme without colors when the
+// NO_COLOR environment variable is set.
+func LoadTheme(name string) (*Theme, error) {
+	theme, ok := themes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q, available options: dark, light, solarized, high-contrast", name)
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return noColorTheme, nil
+	}
+
+	return theme, nil
+}
+
+// apply sets the colors tview uses for the widgets.
+func (t *Theme) apply() {
+	tview.Styles.PrimitiveBackgroundColor = tcell.ColorDefault
+	tview.Styles.ContrastBackgroundColor = t.Field
+	tview.Styles.MoreContrastBackgroundColor = t.Selected
+	tview.Styles.BorderColor = t.Border
+	tview.Styles.TitleColor = t.Title
+	tview.Styles.GraphicsColor = t.Border
+	tview.Styles.PrimaryTextColor = t.Text
+	tview.Styles.SecondaryTextColor = t.Accent
+	tview.Styles.TertiaryTextColor = t.Saved
+	tview.Styles.InverseTextColor = t.Id
+	tview.Styles.ContrastSecondaryTextColor = t.Muted
+}
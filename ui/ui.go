@@ -1,52 +1,68 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
-	_ "unsafe"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/gocolly/colly"
 	"github.com/quantumsheep/go-nyaa/v2/nyaa"
 	"github.com/quantumsheep/go-nyaa/v2/types"
+	"github.com/quantumsheep/nyaa-cli/cache"
+	"github.com/quantumsheep/nyaa-cli/config"
 	"github.com/quantumsheep/nyaa-cli/engine"
+	"github.com/quantumsheep/nyaa-cli/history"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/quantumsheep/nyaa-cli/parser"
+	"github.com/quantumsheep/nyaa-cli/remote"
 	"github.com/quantumsheep/nyaa-cli/utils"
 	"github.com/rivo/tview"
 )
 
-//go:linkname colorPattern github.com/rivo/tview.colorPattern
-var colorPattern *regexp.Regexp
+var filterOptions = optionLabels(utils.Filters())
 
-func init() {
-	// Shady patch to disable color pattern matching in tview
-	colorPattern = regexp.MustCompile(`$^`)
+var resolutionOptions = []string{
+	"Any",
+	"2160p",
+	"1080p",
+	"720p",
+	"480p",
 }
 
-var sortOptions = []string{
-	"Date",
-	"Downloads",
-	"Size",
-	"Seeders",
-	"Leechers",
-	"Comments",
+var releaseOptions = []string{
+	"All",
+	"Episodes",
+	"Batches",
 }
 
-var orderOptions = []string{
-	"Desc",
-	"Asc",
+var viewOptions = []string{
+	"Flat",
+	"Grouped",
 }
 
+var sortOptions = optionLabels(utils.SortKeys())
+
+var orderOptions = optionLabels(utils.Orders())
+
 type Torrent struct {
 	types.Torrent
 
-	hasExpanded bool
-	fileCount   int
+	id      string
+	release parser.Release
+
+	isSaved    bool
+	isSelected bool
+
+	// Files of the torrent once listed, shown below it when expanded
+	files    []*torrentFile
+	expanded bool
+
+	// Indices of the files ticked for download, every file when empty
+	selectedFiles map[int]bool
 }
 
 type UI struct {
@@ -55,58 +71,239 @@ type UI struct {
 	app   *tview.Application
 	pages *tview.Pages
 
-	query   string
-	sortBy  int
-	orderBy int
+	query    string
+	category int
+	filter   int
+	sortBy   int
+	orderBy  int
+
+	resolution int
+	release    int
+	view       int
 
+	localFilter        *localFilter
+	localSort          int
+	localSortAscending bool
+
+	// Query of the displayed results and the pattern of the terms highlighted
+	// in the names
+	searchedQuery string
+	highlight     *regexp.Regexp
+
+	layout     *tview.Flex
 	searchForm *tview.Form
+	filterBar  *tview.InputField
 	table      *tview.Table
 	shortcuts  *tview.Table
+	// Context of the focused widget, its bindings are shown in the shortcut
+	// bar
+	focusContext string
+	keymap       map[string][]string
+	theme        *Theme
+	searches     *tview.List
+
+	continueWatching *tview.List
+	libraryList      *tview.List
+	downloadList     *tview.List
+
+	history *history.History
+	watched *history.Watched
+	library *library.Library
 
 	torrents map[string]*Torrent
+	results  []*Torrent
+	// Files of the torrents already listed, by GUID
+	fileLists map[string][]*torrentFile
+	// Groups of the grouped view showing their alternatives, by group key
+	expandedGroups map[string]bool
+
+	// Columns shown in the table and their position
+	visibleColumns []int
+	columnIndexes  map[int]int
 
 	engine *engine.Engine
+	cache  *cache.Cache
+
+	// Torrents waiting to be downloaded, shared with RunDownloads
+	pendingDownloads []*download
+	downloadsMutex   sync.Mutex
+	// Wakes RunDownloads up, created with the first download
+	downloadsQueued chan struct{}
+	// Every download queued, listed in the downloads page
+	downloadJobs []*download
 }
 
 type UIOptions struct {
 	VideoPlayer     string
 	Fullscreen      bool
 	OutputDirectory string
+	// Directory scanned by the library, empty when none is configured
+	LibraryDirectory string
+	DataDirectory    string
+	CacheSize        uint64
+	Port             int
+	// Time after which a download making no progress fails, zero to wait
+	// forever
+	StallTimeout time.Duration
+
+	SubtitlesDirectory string
+
+	Provider string
+	Category string
+	Filter   string
+	SortBy   string
+	OrderBy  string
+
+	// Remote torrent client, nil when none is configured
+	ClientProfile *config.ClientProfile
+
+	// Columns of the results table, the name is always shown
+	Columns []string
+
+	// Keys bound to the actions, by full name of action, replacing the
+	// default ones
+	Keymap map[string][]string
+
+	// Colors of the interface, see LoadTheme
+	Theme *Theme
+
+	SavedSearch string
 }
 
 func NewUI(options *UIOptions) *UI {
 	ui := &UI{
-		options: options,
-		app:     tview.NewApplication(),
-		query:   "",
-		sortBy:  0,
-		orderBy: 0,
-		pages:   tview.NewPages(),
+		options:  options,
+		app:      tview.NewApplication(),
+		query:    "",
+		category: optionIndex(utils.Categories(options.Provider), options.Category),
+		filter:   optionIndex(filterOptions, options.Filter),
+		sortBy:   optionIndex(sortOptions, options.SortBy),
+		orderBy:  optionIndex(orderOptions, options.OrderBy),
+		pages:    tview.NewPages(),
+		keymap:   options.Keymap,
+		theme:    options.Theme,
+	}
+
+	ui.theme.apply()
+
+	var err error
+	ui.history, err = history.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ui.watched, err = history.LoadWatched()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ui.library, err = library.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ui.cache = &cache.Cache{
+		Directory: options.DataDirectory,
+		MaxSize:   options.CacheSize,
+		Pinned:    ui.library.Pins,
 	}
 
 	ui.app.
 		SetRoot(ui.pages, true).
 		EnableMouse(true)
 
+	ui.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if ui.typing(event) || ui.focusBinds(event) {
+			return event
+		}
+
+		if action, _ := ui.Action(contextGlobal, event); action == actionQuit {
+			ui.app.Stop()
+			return nil
+		}
+
+		return event
+	})
+
+	ui.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		width, _ := screen.Size()
+		if ui.LayoutColumns(width) {
+			row, _ := ui.table.GetSelection()
+			ui.FillTable()
+			ui.table.Select(row, 0)
+		}
+
+		return false
+	})
+
 	ui.GenerateTable()
+	ui.LayoutColumns(80)
 	ui.GenerateSearchForm()
+	ui.GenerateFilterBar()
 	ui.GenerateShortcuts()
 
-	flex := tview.NewFlex().
+	ui.layout = tview.NewFlex().
 		AddItem(ui.searchForm, 5, 0, true).SetDirection(tview.FlexRow).
 		AddItem(ui.table, 0, 6, true).SetDirection(tview.FlexRow).
+		AddItem(ui.filterBar, 0, 0, false).SetDirection(tview.FlexRow).
 		AddItem(ui.shortcuts, 1, 0, false).SetDirection(tview.FlexRow)
 
-	ui.pages.AddPage("search", flex, true, true)
+	ui.layout.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if ui.typing(event) || ui.focusBinds(event) {
+			return event
+		}
 
-	err := ui.Search(nyaa.SearchOptions{
-		Provider: "nyaa",
-		Query:    ui.query,
-		Category: "anime-eng",
-		SortBy:   strings.ToLower(sortOptions[ui.sortBy]),
-		OrderBy:  strings.ToLower(orderOptions[ui.orderBy]),
-		Filter:   "no-filter",
+		action, event := ui.Action(contextGlobal, event)
+		switch action {
+		case actionSearches:
+			ui.ShowSearches()
+		case actionSaveSearch:
+			ui.ShowSaveSearch()
+		case actionContinueWatching:
+			ui.ShowContinueWatching()
+		case actionLibrary:
+			ui.ShowLibrary()
+		case actionDownloads:
+			ui.ShowDownloads()
+		case actionHelp:
+			ui.ShowHelp()
+		default:
+			return event
+		}
+
+		return nil
 	})
+
+	ui.pages.AddPage("search", ui.layout, true, true)
+	ui.GenerateSearchesPage()
+	ui.GenerateContinueWatchingPage()
+	ui.GenerateLibraryPage()
+	ui.GenerateDownloadsPage()
+
+	ui.SetFocusContext(ui.table, contextResults)
+	ui.SetFocusContext(ui.filterBar, contextFilter)
+	ui.SetFocusContext(ui.searches, contextSearches)
+	ui.SetFocusContext(ui.continueWatching, contextContinue)
+	ui.SetFocusContext(ui.libraryList, contextLibrary)
+	ui.SetFocusContext(ui.downloadList, contextDownloads)
+	for i := 0; i < ui.searchForm.GetFormItemCount(); i++ {
+		ui.SetFocusContext(ui.searchForm.GetFormItem(i).(focusable), contextForm)
+	}
+	for i := 0; i < ui.searchForm.GetButtonCount(); i++ {
+		ui.SetFocusContext(ui.searchForm.GetButton(i), contextForm)
+	}
+	ui.UpdateShortcuts(contextForm)
+
+	if options.SavedSearch != "" {
+		saved, ok := ui.history.FindSaved(options.SavedSearch)
+		if !ok {
+			log.Fatal(fmt.Errorf("no saved search named %q", options.SavedSearch))
+		}
+
+		ui.ApplySearch(saved)
+	}
+
+	err = ui.Search(ui.SearchOptions())
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -126,76 +323,224 @@ func (ui *UI) GenerateSearchForm() {
 		SetBorder(true).
 		SetBackgroundColor(tcell.ColorReset)
 
+	ui.searchForm.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if ui.typing(event) {
+			return event
+		}
+
+		_, event = ui.Action(contextForm, event)
+		return event
+	})
+
 	ui.searchForm.SetCancelFunc(func() {
 		ui.app.SetFocus(ui.table)
 	})
 
 	ui.searchForm.
-		AddInputField("Query", "", 24, nil, func(text string) {
+		AddInputField("Query", ui.query, 24, nil, func(text string) {
 			ui.query = text
 		}).
+		AddDropDown("Category", ui.Categories(), ui.category, func(option string, optionIndex int) {
+			ui.category = optionIndex
+		}).
+		AddDropDown("Filter", filterOptions, ui.filter, func(option string, optionIndex int) {
+			ui.filter = optionIndex
+		}).
 		AddDropDown("Sort By", sortOptions, ui.sortBy, func(option string, optionIndex int) {
 			ui.sortBy = optionIndex
 		}).
-		AddDropDown("Order By", orderOptions, ui.sortBy, func(option string, optionIndex int) {
+		AddDropDown("Order By", orderOptions, ui.orderBy, func(option string, optionIndex int) {
 			ui.orderBy = optionIndex
 		}).
+		AddDropDown("Resolution", resolutionOptions, ui.resolution, func(option string, optionIndex int) {
+			ui.resolution = optionIndex
+			ui.FillTable()
+		}).
+		AddDropDown("Release", releaseOptions, ui.release, func(option string, optionIndex int) {
+			ui.release = optionIndex
+			ui.FillTable()
+		}).
+		AddDropDown("View", viewOptions, ui.view, func(option string, optionIndex int) {
+			ui.view = optionIndex
+			ui.FillTable()
+		}).
 		AddButton("Search", func() {
-			err := ui.Search(nyaa.SearchOptions{
-				Provider: "nyaa",
-				Query:    ui.query,
-				Category: "anime-eng",
-				SortBy:   strings.ToLower(sortOptions[ui.sortBy]),
-				OrderBy:  strings.ToLower(orderOptions[ui.orderBy]),
-				Filter:   "no-filter",
-			})
-			if err != nil {
-				ui.Fatal(err)
-			}
-
-			ui.app.SetFocus(ui.table)
+			ui.RunSearch()
 		})
 }
 
+func (ui *UI) Categories() []string {
+	return utils.Categories(ui.options.Provider)
+}
+
+func (ui *UI) SearchOptions() nyaa.SearchOptions {
+	return nyaa.SearchOptions{
+		Provider: ui.options.Provider,
+		Query:    ui.query,
+		Category: ui.Categories()[ui.category],
+		SortBy:   optionValue(sortOptions[ui.sortBy]),
+		OrderBy:  optionValue(orderOptions[ui.orderBy]),
+		Filter:   optionValue(filterOptions[ui.filter]),
+	}
+}
+
+// RunSearch searches with the values of the search form and records the
+// query in the search history.
+func (ui *UI) RunSearch() {
+	if err := ui.Search(ui.SearchOptions()); err != nil {
+		ui.Fatal(err)
+	}
+
+	ui.history.AddQuery(ui.query)
+	if err := ui.history.Save(); err != nil {
+		ui.Fatal(err)
+	}
+
+	ui.app.SetFocus(ui.table)
+}
+
 func (ui *UI) Search(opts nyaa.SearchOptions) error {
-	torrents, err := nyaa.Search(opts)
+	torrents, err := utils.Search(opts)
 	if err != nil {
 		return err
 	}
 
 	ui.torrents = make(map[string]*Torrent)
-	ui.table.Clear()
+	ui.results = nil
+	ui.searchedQuery = opts.Query
 
-	for i, torrent := range torrents {
+	for _, torrent := range torrents {
 		link := strings.Split(torrent.Link, "download/")
 		id := strings.TrimSuffix(link[1], ".torrent")
 
-		t, _ := time.Parse("Mon, 02 Jan 2006 15:04:05 -0700", torrent.Date)
-		date := t.Format("2006-01-02 15:04")
+		t := &Torrent{
+			Torrent: torrent,
+			id:      id,
+			release: parser.Parse(torrent.Name),
+		}
+
+		ui.torrents[id] = t
+		ui.results = append(ui.results, t)
+	}
+
+	ui.FillTable()
+
+	return nil
+}
+
+// FillTable renders the results of the last search matching the resolution
+// and release filters, below the header.
+func (ui *UI) FillTable() {
+	ui.table.Clear()
+	ui.SetHeader()
+
+	ui.highlight = highlightPattern(ui.searchedQuery, ui.localFilter)
 
-		trusted := ""
-		if torrent.IsTrusted == "Yes" {
-			trusted = "✓"
+	var torrents []*Torrent
+	for _, torrent := range ui.results {
+		if ui.MatchesFilters(torrent) {
+			torrents = append(torrents, torrent)
 		}
+	}
 
-		ui.table.SetCell(i, 0, ui.GenerateCell(id, 8, 0, tcell.ColorBlue))
-		ui.table.SetCell(i, 1, ui.GenerateCell("○", 4, 0, tcell.ColorWhite))
-		ui.table.SetCell(i, 2, ui.GenerateCell(torrent.Size, 10, 0, tcell.ColorYellow))
-		ui.table.SetCell(i, 3, ui.GenerateCell(date, 17, 0, tcell.ColorGray))
-		ui.table.SetCell(i, 4, ui.GenerateCell(torrent.Seeders, 6, 0, tcell.ColorGreen))
-		ui.table.SetCell(i, 5, ui.GenerateCell(torrent.Leechers, 6, 0, tcell.ColorRed))
-		ui.table.SetCell(i, 6, ui.GenerateCell(trusted, 2, 0, tcell.ColorWhite))
-		ui.table.SetCell(i, 7, ui.GenerateCell(torrent.Name, 0, 0, tcell.ColorWhite).SetAlign(tview.AlignLeft).SetExpansion(1))
+	ui.sortTorrents(torrents)
 
-		ui.torrents[id] = &Torrent{
-			Torrent: torrent,
+	row := 1
+	if viewOptions[ui.view] == "Grouped" {
+		for _, group := range groupTorrents(torrents) {
+			expanded := ui.expandedGroups[groupKey(group[0])]
+			row = ui.SetTorrentRows(row, group[0], groupPrefix(len(group), expanded))
+
+			if expanded {
+				for _, torrent := range group[1:] {
+					row = ui.SetTorrentRows(row, torrent, alternativePrefix)
+				}
+			}
+		}
+	} else {
+		for _, torrent := range torrents {
+			row = ui.SetTorrentRows(row, torrent, "")
 		}
 	}
 
-	ui.table.Select(0, 0)
+	ui.table.Select(1, 0)
 	ui.table.ScrollToBeginning()
+}
 
-	return nil
+// SetTorrentRows renders a torrent followed by its files when expanded, and
+// returns the next row.
+func (ui *UI) SetTorrentRows(row int, torrent *Torrent, prefix string) int {
+	ui.SetTorrentRow(row, torrent, prefix)
+	row++
+
+	if torrent.expanded {
+		for i := range torrent.files {
+			ui.SetFileRow(row, torrent, i)
+			row++
+		}
+	}
+
+	return row
+}
+
+// SetTorrentRow renders a torrent on the given row, the prefix of its name
+// tells its place in the grouped view.
+func (ui *UI) SetTorrentRow(row int, torrent *Torrent, prefix string) {
+	date := torrentDate(torrent).Format("2006-01-02 15:04")
+
+	trusted := ""
+	if torrent.IsTrusted == "Yes" {
+		trusted = "✓"
+	}
+
+	remake := ""
+	if torrent.IsRemake == "Yes" {
+		remake = "R"
+	}
+
+	nameColor := ui.theme.Text
+	if prefix == alternativePrefix {
+		nameColor = ui.theme.Alternative
+	}
+
+	ui.setCell(row, columnId, ui.GenerateCell(torrent.id, 8, 0, ui.theme.Id))
+	if torrent.isSaved {
+		ui.setCell(row, columnSaved, ui.GenerateCell("●", 4, 0, ui.theme.Saved))
+	} else {
+		ui.setCell(row, columnSaved, ui.GenerateCell("○", 4, 0, ui.theme.Text))
+	}
+	ui.setCell(row, columnWatched, ui.GenerateWatchedCell(torrent.GUID, -2))
+	ui.setCell(row, columnSize, ui.GenerateCell(torrent.Size, 10, 0, ui.theme.Size))
+	ui.setCell(row, columnDate, ui.GenerateCell(date, 17, 0, ui.theme.Date))
+	ui.setCell(row, columnSeeders, ui.GenerateCell(torrent.Seeders, 6, 0, ui.theme.Seeders))
+	ui.setCell(row, columnLeechers, ui.GenerateCell(torrent.Leechers, 6, 0, ui.theme.Leechers))
+	ui.setCell(row, columnDownloads, ui.GenerateCell(torrent.Downloads, 7, 0, ui.theme.Text))
+	ui.setCell(row, columnTrusted, ui.GenerateCell(trusted, 2, 0, ui.theme.Saved))
+	ui.setCell(row, columnRemake, ui.GenerateCell(remake, 2, 0, ui.theme.Remake))
+	ui.setCell(row, columnCategory, ui.GenerateCell(torrent.Category, 0, 0, ui.theme.Muted).SetAlign(tview.AlignLeft).SetMaxWidth(24))
+	ui.setCell(row, columnResolution, ui.GenerateCell(torrent.release.Resolution, 6, 0, ui.theme.Resolution))
+	ui.setCell(row, columnEpisode, ui.GenerateCell(torrent.release.EpisodeLabel(), 8, 0, ui.theme.Episode))
+	ui.setCell(row, columnName, ui.GenerateNameCell(prefix, torrent.Name, nameColor).SetReference(&tableRow{torrent: torrent, index: -1}))
+	ui.SetRowSelected(row, torrent.isSelected)
+}
+
+func (ui *UI) MatchesFilters(torrent *Torrent) bool {
+	if ui.localFilter != nil && !ui.localFilter.matches(torrent) {
+		return false
+	}
+
+	if ui.resolution > 0 && torrent.release.Resolution != resolutionOptions[ui.resolution] {
+		return false
+	}
+
+	switch releaseOptions[ui.release] {
+	case "Episodes":
+		return !torrent.release.Batch
+	case "Batches":
+		return torrent.release.Batch
+	}
+
+	return true
 }
 
 func (ui *UI) GenerateCell(value string, leftPadding int, rightPadding int, color tcell.Color) *tview.TableCell {
@@ -207,7 +552,7 @@ func (ui *UI) GenerateCell(value string, leftPadding int, rightPadding int, colo
 		value = value + strings.Repeat(" ", rightPadding-len(value))
 	}
 
-	return tview.NewTableCell(value).
+	return tview.NewTableCell(tview.Escape(value)).
 		SetTextColor(color).
 		SetAlign(tview.AlignRight)
 }
@@ -219,209 +564,193 @@ func (ui *UI) GenerateTable() {
 		SetBorder(true).
 		SetBackgroundColor(tcell.ColorReset)
 
-	ui.table.SetDoneFunc(func(key tcell.Key) {
-		ui.app.SetFocus(ui.searchForm)
-	})
-
 	ui.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		row, _ := ui.table.GetSelection()
 
-		if event.Key() == tcell.KeyF2 {
-			id, _ := ui.GetTorrentId(row)
-			torrent := ui.torrents[id]
-
-			directory, err := filepath.Abs(ui.options.OutputDirectory)
-			if err != nil {
-				ui.Fatal(err)
-			}
-
-			if err := os.MkdirAll(directory, os.ModePerm); err != nil {
-				ui.Fatal(err)
-			}
-
-			_, err = utils.Download(torrent.Link, filepath.Join(directory, torrent.Name+".torrent"))
-			if err != nil {
-				ui.Fatal(err)
-			}
-
-			ui.table.SetCell(row, 1, ui.GenerateCell("●", 4, 0, tcell.ColorGreen).SetAlign(tview.AlignRight))
-
-			return nil
+		action, event := ui.Action(contextResults, event)
+		switch action {
+		case actionOpen:
+			ui.OpenRow(row)
+		case actionExpand:
+			ui.Expand(row)
+		case actionCollapse:
+			ui.Collapse(row)
+		case actionToggleGroup:
+			ui.ToggleGroup(row)
+		case actionSelect:
+			ui.ToggleSelection(row)
+		case actionSelectAll:
+			ui.SelectAll()
+		case actionFilter:
+			ui.ShowFilterBar()
+		case actionSort:
+			ui.CycleLocalSort()
+		case actionReverseSort:
+			ui.ReverseLocalSort()
+		case actionSaveTorrent:
+			ui.SaveTorrentFiles(ui.Targets(row))
+		case actionCopyMagnet, actionCopyInfoHash:
+			ui.CopyTorrents(ui.Targets(row), action == actionCopyMagnet)
+		case actionSend:
+			ui.SendTorrents(ui.Targets(row))
+		case actionDownload:
+			ui.QueueDownloads(ui.Targets(row))
+		case actionBack:
+			ui.app.SetFocus(ui.searchForm)
+		default:
+			return event
 		}
 
-		return event
+		return nil
 	})
 
 	ui.table.SetSelectedFunc(func(row int, column int) {
-		id, index := ui.GetTorrentId(row)
-		torrent := ui.torrents[id]
-
-		if !torrent.hasExpanded {
-			files, err := nyaaTorrentFiles(torrent.GUID)
-			if err != nil {
-				ui.Fatal(err)
-			}
+		ui.OpenRow(row)
+	})
+}
 
-			torrent.fileCount = len(files)
-
-			if len(files) > 1 {
-				for i, file := range files {
-					newRow := row + 1 + i
-
-					ui.table.InsertRow(newRow)
-					ui.table.SetCell(newRow, 0, ui.GenerateCell("", 8, 0, tcell.ColorWhite).SetAlign(tview.AlignLeft))
-					ui.table.SetCell(newRow, 1, ui.GenerateCell("│", 4, 0, tcell.ColorWhite))
-					ui.table.SetCell(newRow, 2, ui.GenerateCell(file.size, 10, 0, tcell.ColorYellow))
-					ui.table.SetCell(newRow, 3, ui.GenerateCell("", 17, 0, tcell.ColorYellow))
-					ui.table.SetCell(newRow, 4, ui.GenerateCell("", 6, 0, tcell.ColorYellow))
-					ui.table.SetCell(newRow, 5, ui.GenerateCell("", 6, 0, tcell.ColorYellow))
-					ui.table.SetCell(newRow, 6, ui.GenerateCell("", 2, 0, tcell.ColorYellow))
-					ui.table.SetCell(newRow, 7, ui.GenerateCell(file.name, 0, 0, tcell.ColorDimGray).SetAlign(tview.AlignLeft).SetExpansion(1))
-				}
+// OpenRow shows or hides the files of the torrent of the row, or plays the
+// torrent or the file of the row.
+func (ui *UI) OpenRow(row int) {
+	r := ui.Row(row)
+	if r == nil {
+		return
+	}
 
-				torrent.hasExpanded = true
-				return
-			} else {
-				torrent.hasExpanded = true
-			}
-		}
+	torrent, index := r.torrent, r.index
 
-		if torrent.fileCount > 1 && index == -1 {
+	if index == -1 {
+		if torrent.expanded {
+			ui.Collapse(row)
 			return
 		}
 
-		if ui.engine == nil {
-			tempDir, err := os.MkdirTemp("", "nyaa-cli")
-			if err != nil {
-				ui.Fatal(err)
-			}
-
-			ui.engine, err = engine.NewEngine(tempDir)
-			if err != nil {
-				ui.Fatal(err)
-			}
+		if ui.Expand(row) {
+			return
 		}
+	}
 
-		ui.app.Suspend(func() {
-			if err := ui.engine.SetTorrentFromPath(torrent.Link); err != nil {
-				ui.Fatal(err)
-			}
-			defer ui.engine.DropCurrentTorrent()
-
-			port := "3001"
-			url := fmt.Sprintf("http://localhost:%s", port)
-
-			if index > -1 {
-				url += fmt.Sprintf("/%d", index)
-			}
-
-			wg := sync.WaitGroup{}
-			wg.Add(3)
+	path := ""
+	if index > -1 {
+		path = torrent.files[index].path
+	}
 
-			go func() {
-				defer wg.Done()
+	ui.Play(torrent.GUID, torrent.Link, index, path)
 
-				if err := ui.engine.RunServer(port, 0); err != nil {
-					ui.Fatal(err)
-				}
-			}()
-
-			go func() {
-				defer wg.Done()
-				ui.engine.RunStatusLoop(index)
-			}()
-
-			go func() {
-				defer wg.Done()
-
-				err := utils.RunVideoPlayer(utils.VideoPlayerConfig{
-					VideoPlayer: ui.options.VideoPlayer,
-					Url:         url,
-					Name:        ui.engine.GetFileName(index),
-					OnTop:       true,
-					Fullscreen:  ui.options.Fullscreen,
-				})
-				if err != nil {
-					ui.Fatal(err)
-				}
+	if index > -1 {
+		ui.setCell(row, columnWatched, ui.GenerateWatchedFileCell(torrent.GUID, path))
+		ui.SetRowSelected(row, torrent.selectedFiles[index])
+	}
 
-				ui.engine.StopStatusLoop()
+	if torrentRow := ui.torrentRow(torrent); torrentRow >= 0 {
+		ui.setCell(torrentRow, columnWatched, ui.GenerateWatchedCell(torrent.GUID, -2))
+		ui.SetRowSelected(torrentRow, torrent.isSelected)
+	}
+}
 
-				err = ui.engine.StopServer()
-				if err != nil {
-					ui.Fatal(err)
-				}
-			}()
+// GenerateWatchedCell marks the file of the torrent as watched when it's in
+// the watch history, or any file of the torrent when index is -2.
+func (ui *UI) GenerateWatchedCell(guid string, index int) *tview.TableCell {
+	watched := false
+	if index == -2 {
+		watched = ui.watched.HasWatched(guid)
+	} else {
+		_, watched = ui.watched.Find(guid, index)
+	}
 
-			wg.Wait()
-		})
-	})
+	return ui.generateWatchedCell(watched)
 }
 
-func (ui *UI) GetTorrentId(row int) (string, int) {
-	id := strings.TrimSpace(ui.table.GetCell(row, 0).Text)
-	index := -1
+// GenerateWatchedFileCell marks the file of the torrent at the given path as
+// watched when it's in the watch history.
+func (ui *UI) GenerateWatchedFileCell(guid string, path string) *tview.TableCell {
+	_, watched := ui.watched.FindFile(guid, path)
+	return ui.generateWatchedCell(watched)
+}
 
-	for id == "" {
-		id = strings.TrimSpace(ui.table.GetCell(row-2-index, 0).Text)
-		index++
+func (ui *UI) generateWatchedCell(watched bool) *tview.TableCell {
+	if watched {
+		return ui.GenerateCell("▶", 2, 0, ui.theme.Watched)
 	}
 
-	return id, index
+	return ui.GenerateCell("", 2, 0, ui.theme.Text)
 }
 
 func (ui *UI) GenerateShortcuts() {
 	ui.shortcuts = tview.NewTable().
-		SetBorders(false).
-		SetCell(0, 0, tview.NewTableCell("F1").
-			SetTextColor(tcell.ColorWhite).
+		SetBorders(false)
+}
+
+func (ui *UI) AddShortcut(key string, label string) {
+	column := ui.shortcuts.GetColumnCount()
+
+	ui.shortcuts.
+		SetCell(0, column, tview.NewTableCell(tview.Escape(key)).
+			SetTextColor(ui.theme.ShortcutKey).
 			SetAlign(tview.AlignCenter),
 		).
-		SetCell(0, 1, tview.NewTableCell("Download").
-			SetTextColor(tcell.ColorBlack).
-			SetBackgroundColor(tcell.ColorBlue).
+		SetCell(0, column+1, tview.NewTableCell(label).
+			SetTextColor(ui.theme.ShortcutLabel).
+			SetBackgroundColor(ui.theme.ShortcutBackground).
+			SetAttributes(ui.theme.ShortcutAttributes).
 			SetAlign(tview.AlignCenter),
 		)
 }
 
-func (ui *UI) Fatal(err error) {
-	ui.app.Stop()
-	log.Fatal(err)
-}
+// optionLabels names the values like "no-filter" the way the search form shows
+// them, like "No filter".
+func optionLabels(values []string) []string {
+	labels := make([]string, len(values))
+	for i, value := range values {
+		label := strings.ReplaceAll(value, "-", " ")
+		labels[i] = strings.ToUpper(label[:1]) + label[1:]
+	}
 
-type torrentFile struct {
-	name string
-	size string
+	return labels
 }
 
-func nyaaTorrentFiles(viewURL string) ([]*torrentFile, error) {
-	var files []*torrentFile
-
-	c := colly.NewCollector()
-
-	c.OnHTML(".torrent-file-list li", func(e *colly.HTMLElement) {
-		if e.DOM.Has("ul").Length() == 0 {
-			fileSize := e.ChildText(".file-size")
+func optionValue(option string) string {
+	return strings.ReplaceAll(strings.ToLower(option), " ", "-")
+}
 
-			files = append(files, &torrentFile{
-				name: strings.TrimSuffix(e.Text, " "+fileSize),
-				size: fileSize[1 : len(fileSize)-1], // remove "(" and ")"
-			})
+func optionIndex(options []string, value string) int {
+	for i, option := range options {
+		if optionValue(option) == value {
+			return i
 		}
-	})
+	}
 
-	var e error
-	c.OnError(func(r *colly.Response, err error) {
-		e = err
-	})
-	if e != nil {
-		return nil, e
+	return 0
+}
+
+// SendToClient adds the torrent to the configured remote torrent client.
+func (ui *UI) SendToClient(torrent *Torrent) error {
+	if ui.options.ClientProfile == nil {
+		return errors.New("no remote client configured")
 	}
 
-	err := c.Visit(viewURL)
+	client, err := remote.New(ui.options.ClientProfile)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	link := torrent.Link
+	if ui.options.ClientProfile.Magnet {
+		link, err = utils.Magnet(ui.options.Provider, torrent.Link, torrent.InfoHash, torrent.Name)
+		if err != nil {
+			return err
+		}
 	}
 
-	return files, nil
+	return client.Add(link)
+}
+
+// Notify shows a message in the title of the results table.
+func (ui *UI) Notify(message string) {
+	ui.table.SetTitle(" " + tview.Escape(message) + " ")
+}
+
+func (ui *UI) Fatal(err error) {
+	ui.app.Stop()
+	log.Fatal(err)
 }
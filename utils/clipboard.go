@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// CopyToClipboard asks the terminal to copy the text to the system clipboard
+// with the OSC 52 escape sequence, which also works over SSH.
+func CopyToClipboard(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		// Windows has no /dev/tty, the console is the standard output
+		tty = os.Stdout
+	} else {
+		defer tty.Close()
+	}
+
+	_, err = fmt.Fprintf(tty, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
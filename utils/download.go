@@ -2,12 +2,13 @@ package utils
 
 import (
 	"io"
-	"net/http"
 	"os"
 )
 
+// Download saves the response of the URL into the destination, which is only
+// created once the request succeeded.
 func Download(url string, destination string) (string, error) {
-	res, err := http.Get(url)
+	res, err := Get(url)
 	if err != nil {
 		return "", err
 	}
@@ -20,6 +21,8 @@ func Download(url string, destination string) (string, error) {
 	defer file.Close()
 
 	if _, err := io.Copy(file, res.Body); err != nil {
+		file.Close()
+		os.Remove(destination)
 		return "", err
 	}
 
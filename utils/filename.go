@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Most file systems limit names to 255 bytes
+const maxFilenameLength = 255
+
+// SafeFilename replaces the characters that aren't allowed in file names on
+// any platform and truncates the name so the extension fits.
+func SafeFilename(name string, extension string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	// Windows doesn't allow trailing dots and spaces, leading ones hide files
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		name = "torrent"
+	}
+
+	for len(name)+len(extension) > maxFilenameLength {
+		runes := []rune(name)
+		name = string(runes[:len(runes)-1])
+	}
+
+	return name + extension
+}
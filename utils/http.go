@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPOptions configures the client shared by the searches, the downloads of
+// the .torrent files and the scraping of nyaa.
+type HTTPOptions struct {
+	Timeout time.Duration
+	// Number of times a failed request is sent again
+	Retries   int
+	UserAgent string
+	// URL of an HTTP or SOCKS5 proxy like "socks5://localhost:1080", the
+	// HTTP_PROXY and HTTPS_PROXY environment variables are used when empty
+	Proxy string
+}
+
+var DefaultHTTPOptions = HTTPOptions{
+	Timeout:   30 * time.Second,
+	Retries:   3,
+	UserAgent: "nyaa-cli",
+}
+
+// Delay before the first retry, doubled for each of the next ones
+const retryBackoff = 500 * time.Millisecond
+
+var httpClient = newHTTPClient(DefaultHTTPOptions, nil)
+
+// StatusError is returned for the responses that aren't successful.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// ConfigureHTTP replaces the shared client.
+func ConfigureHTTP(options HTTPOptions) error {
+	var proxy *url.URL
+	if options.Proxy != "" {
+		var err error
+		proxy, err = ParseProxy(options.Proxy)
+		if err != nil {
+			return err
+		}
+	}
+
+	httpClient = newHTTPClient(options, proxy)
+	return nil
+}
+
+// ParseProxy checks the URL of a proxy.
+func ParseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", proxy, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	}
+
+	return nil, fmt.Errorf("invalid proxy %q, the scheme must be http, https or socks5", proxy)
+}
+
+func newHTTPClient(options HTTPOptions, proxy *url.URL) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &http.Client{
+		Timeout: options.Timeout,
+		Transport: &retryTransport{
+			base:      transport,
+			retries:   options.Retries,
+			userAgent: options.UserAgent,
+		},
+	}
+}
+
+// HTTPClient returns the shared client.
+func HTTPClient() *http.Client {
+	return httpClient
+}
+
+// Get requests the URL with the shared client, the responses that aren't
+// successful are returned as a StatusError.
+func Get(url string) (*http.Response, error) {
+	res, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		res.Body.Close()
+		return nil, &StatusError{URL: url, StatusCode: res.StatusCode}
+	}
+
+	return res, nil
+}
+
+// retryTransport sets the user agent of the requests and sends the GET and
+// HEAD requests again when they fail with a network error, a server error or
+// a rate limit.
+type retryTransport struct {
+	base      http.RoundTripper
+	retries   int
+	userAgent string
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		res, err := t.base.RoundTrip(req)
+
+		idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+		if attempt >= t.retries || !idempotent || !shouldRetry(res, err) {
+			return res, err
+		}
+
+		if res != nil {
+			res.Body.Close()
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		backoff *= 2
+	}
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
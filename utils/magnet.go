@@ -0,0 +1,39 @@
+package utils
+
+import "github.com/anacrolix/torrent/metainfo"
+
+// Magnet builds the magnet link of the torrent from the announce list of its
+// .torrent file at the URL. When the .torrent file can't be fetched, the link
+// is built from the infohash with the trackers of the provider, unless the
+// infohash is empty.
+func Magnet(provider string, url string, infoHash string, name string) (string, error) {
+	magnet, err := metainfoMagnet(url)
+	if err == nil || infoHash == "" {
+		return magnet, err
+	}
+
+	var hash metainfo.Hash
+	if err := hash.FromHexString(infoHash); err != nil {
+		return "", err
+	}
+
+	return metainfo.Magnet{
+		InfoHash:    hash,
+		DisplayName: name,
+		Trackers:    providerTrackers[provider],
+	}.String(), nil
+}
+
+func metainfoMagnet(url string) (string, error) {
+	mi, err := LoadMetainfo(url)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := mi.UnmarshalInfo()
+	if err != nil {
+		return "", err
+	}
+
+	return mi.Magnet(nil, &info).String(), nil
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestMagnet(t *testing.T) {
+	// The downloaded .torrent files are cached in the user cache directory
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	infoBytes, err := bencode.Marshal(metainfo.Info{Name: "Show", PieceLength: 16, Pieces: make([]byte, 20), Length: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mi := metainfo.MetaInfo{InfoBytes: infoBytes, Announce: "http://tracker.example/announce"}
+	infoHash := mi.HashInfoBytes().HexString()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/download/1.torrent" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		mi.Write(w)
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name     string
+		url      string
+		infoHash string
+		tracker  string
+		wantErr  bool
+	}{
+		{name: "from metainfo", url: server.URL + "/download/1.torrent", tracker: "tracker.example"},
+		{name: "provider trackers", url: server.URL + "/download/2.torrent", infoHash: infoHash, tracker: "nyaa.tracker.wf"},
+		{name: "no infohash", url: server.URL + "/download/2.torrent", wantErr: true},
+		{name: "invalid infohash", url: server.URL + "/download/2.torrent", infoHash: "abc", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			magnet, err := Magnet("nyaa", test.url, test.infoHash, "Show")
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("got %q, want an error", magnet)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(magnet, "xt=urn:btih:"+infoHash) || !strings.Contains(magnet, test.tracker) {
+				t.Errorf("got %q, want the infohash %s and the tracker %s", magnet, infoHash, test.tracker)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// LoadMetainfo reads the metainfo of the .torrent file, which is downloaded
+// once and kept in the user cache directory for the next sessions.
+func LoadMetainfo(url string) (*metainfo.MetaInfo, error) {
+	path, err := metainfoPath(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if mi, err := metainfo.LoadFromFile(path); err == nil {
+		return mi, nil
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".nyaa-cli-*")
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if _, err := Download(url, f.Name()); err != nil {
+		return nil, err
+	}
+
+	mi, err := metainfo.LoadFromFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only valid metainfo is kept, an error page would be cached forever
+	if err := os.Rename(f.Name(), path); err != nil {
+		return nil, err
+	}
+
+	return mi, nil
+}
+
+// metainfoPath is the path of the cached .torrent file downloaded from the
+// URL.
+func metainfoPath(url string) (string, error) {
+	directory, err := CacheDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	directory = filepath.Join(directory, "metainfo")
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	hash := sha1.Sum([]byte(url))
+	return filepath.Join(directory, hex.EncodeToString(hash[:])+".torrent"), nil
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// mpvControl reads the playback position from the mpv JSON IPC.
+type mpvControl struct {
+	address string
+}
+
+func getMpvArgs(config VideoPlayerConfig) ([]string, playerControl) {
+	control := &mpvControl{
+		address: mpvSocketPath(fmt.Sprintf("nyaa-cli-mpv-%d", os.Getpid())),
+	}
+
+	args := []string{
+		"--really-quiet",
+		"--input-ipc-server=" + control.address,
+		"--force-media-title=" + config.Name,
+	}
+
+	if config.OnTop {
+		args = append(args, "--ontop")
+	}
+
+	if config.Fullscreen {
+		args = append(args, "--fs")
+	}
+
+	if config.StartPosition > 0 {
+		args = append(args, fmt.Sprintf("--start=%.0f", config.StartPosition))
+	}
+
+	for _, subtitle := range config.Subtitles {
+		args = append(args, "--sub-file="+subtitle)
+	}
+
+	return append(args, config.Url), control
+}
+
+func (c *mpvControl) Position() (float64, float64, error) {
+	position, err := c.getProperty("time-pos")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	duration, err := c.getProperty("duration")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return position, duration, nil
+}
+
+func (c *mpvControl) getProperty(name string) (float64, error) {
+	conn, err := dialMpv(c.address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	const requestId = 1
+
+	request, err := json.Marshal(map[string]interface{}{
+		"command":    []string{"get_property", name},
+		"request_id": requestId,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := conn.Write(append(request, '\n')); err != nil {
+		return 0, err
+	}
+
+	// mpv also writes events on the socket, only the response is relevant
+	scanner := bufio.NewScanner(conn)
+	deadline := time.Now().Add(2 * time.Second)
+
+	for scanner.Scan() && time.Now().Before(deadline) {
+		var response struct {
+			Data      float64 `json:"data"`
+			RequestId int     `json:"request_id"`
+			Error     string  `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil || response.RequestId != requestId {
+			continue
+		}
+
+		if response.Error != "success" {
+			return 0, fmt.Errorf("mpv: %s", response.Error)
+		}
+
+		return response.Data, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("mpv: no response for %s", name)
+}
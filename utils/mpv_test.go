@@ -0,0 +1,134 @@
+//go:build !windows
+
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeMpv serves the mpv JSON IPC on a unix socket, answering get_property
+// requests with the given properties. A missing property is answered with an
+// error, like mpv does before the video is loaded.
+func fakeMpv(t *testing.T, properties map[string]float64) *mpvControl {
+	t.Helper()
+
+	// Unix socket paths are limited to about 100 bytes, t.TempDir is too long
+	// on some systems
+	directory, err := os.MkdirTemp("", "mpv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(directory) })
+
+	address := filepath.Join(directory, "mpv.sock")
+
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveMpv(conn, properties)
+		}
+	}()
+
+	return &mpvControl{address: address}
+}
+
+func serveMpv(conn net.Conn, properties map[string]float64) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var request struct {
+			Command   []string `json:"command"`
+			RequestId int      `json:"request_id"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			return
+		}
+
+		// Events are written on the socket too and must be skipped
+		conn.Write([]byte(`{"event":"playback-restart"}` + "\n"))
+
+		response := map[string]interface{}{"request_id": request.RequestId, "error": "property unavailable"}
+		if value, ok := properties[request.Command[1]]; ok {
+			response["error"] = "success"
+			response["data"] = value
+		}
+
+		data, _ := json.Marshal(response)
+		conn.Write(append(data, '\n'))
+	}
+}
+
+func TestMpvPosition(t *testing.T) {
+	control := fakeMpv(t, map[string]float64{"time-pos": 754.5, "duration": 1420.2})
+
+	position, duration, err := control.Position()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if position != 754.5 || duration != 1420.2 {
+		t.Errorf("got position %v and duration %v, want 754.5 and 1420.2", position, duration)
+	}
+}
+
+func TestMpvPropertyUnavailable(t *testing.T) {
+	control := fakeMpv(t, map[string]float64{"time-pos": 754.5})
+
+	if _, _, err := control.Position(); err == nil || err.Error() != "mpv: property unavailable" {
+		t.Errorf("got error %v, want mpv: property unavailable", err)
+	}
+}
+
+func TestMpvDisconnected(t *testing.T) {
+	directory, err := os.MkdirTemp("", "mpv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	address := filepath.Join(directory, "mpv.sock")
+
+	// Not running yet
+	control := &mpvControl{address: address}
+	if _, _, err := control.Position(); err == nil {
+		t.Error("expected an error without mpv")
+	}
+
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Closing the connection without answering, like mpv quitting
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			conn.Close()
+		}
+	}()
+
+	if _, _, err := control.Position(); err == nil {
+		t.Error("expected an error when mpv closes the connection")
+	}
+}
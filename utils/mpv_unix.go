@@ -0,0 +1,24 @@
+//go:build !windows
+
+package utils
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func mpvSocketPath(name string) string {
+	return filepath.Join(os.TempDir(), name+".sock")
+}
+
+func dialMpv(address string) (io.ReadWriteCloser, error) {
+	conn, err := net.DialTimeout("unix", address, 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, conn.SetDeadline(time.Now().Add(2 * time.Second))
+}
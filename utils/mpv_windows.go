@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"io"
+	"time"
+
+	"github.com/Microsoft/go-winio"
+)
+
+func mpvSocketPath(name string) string {
+	return `\\.\pipe\` + name
+}
+
+func dialMpv(address string) (io.ReadWriteCloser, error) {
+	timeout := 2 * time.Second
+
+	conn, err := winio.DialPipe(address, &timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, conn.SetDeadline(time.Now().Add(timeout))
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "fmt"
+
+var providerHosts = map[string]string{
+	"nyaa":    "nyaa.si",
+	"sukebei": "sukebei.nyaa.si",
+}
+
+// Trackers announced by the torrents uploaded on each provider
+var providerTrackers = map[string][]string{
+	"nyaa": {
+		"http://nyaa.tracker.wf:7777/announce",
+		"udp://open.stealth.si:80/announce",
+		"udp://tracker.opentrackr.org:1337/announce",
+		"udp://exodus.desync.com:6969/announce",
+		"udp://tracker.torrent.eu.org:451/announce",
+	},
+	"sukebei": {
+		"http://sukebei.tracker.wf:8888/announce",
+		"udp://open.stealth.si:80/announce",
+		"udp://tracker.opentrackr.org:1337/announce",
+		"udp://exodus.desync.com:6969/announce",
+		"udp://tracker.torrent.eu.org:451/announce",
+	},
+}
+
+func ProviderHost(provider string) string {
+	return providerHosts[provider]
+}
+
+func TorrentURL(provider string, id string) string {
+	return fmt.Sprintf("https://%s/download/%s.torrent", ProviderHost(provider), id)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const applicationName = "nyaa-cli"
+
+func ConfigDirectory() (string, error) {
+	directory, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	directory = filepath.Join(directory, applicationName)
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	return directory, nil
+}
+
+func CacheDirectory() (string, error) {
+	directory, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	directory = filepath.Join(directory, applicationName)
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	return directory, nil
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/quantumsheep/go-nyaa/v2/nyaa"
+	"github.com/quantumsheep/go-nyaa/v2/types"
+)
+
+// searchOption is a value of a search option and its code in the search
+// parameters
+type searchOption struct {
+	name string
+	code string
+}
+
+// Values of the search options, in the order they are offered
+var (
+	// By provider
+	categories = map[string][]searchOption{
+		"nyaa": {
+			{"all", "0_0"},
+			{"anime", "1_0"},
+			{"anime-amv", "1_1"},
+			{"anime-eng", "1_2"},
+			{"anime-non-eng", "1_3"},
+			{"anime-raw", "1_4"},
+			{"audio", "2_0"},
+			{"audio-lossless", "2_1"},
+			{"audio-lossy", "2_2"},
+			{"literature", "3_0"},
+			{"literature-eng", "3_1"},
+			{"literature-non-eng", "3_2"},
+			{"literature-raw", "3_3"},
+			{"live-action", "4_0"},
+			{"live-action-eng", "4_1"},
+			{"live-action-idol-prom", "4_2"},
+			{"live-action-non-eng", "4_3"},
+			{"live-action-raw", "4_4"},
+			{"pictures", "5_0"},
+			{"pictures-graphics", "5_1"},
+			{"pictures-photos", "5_2"},
+			{"software", "6_0"},
+			{"software-apps", "6_1"},
+			{"software-games", "6_2"},
+		},
+		"sukebei": {
+			{"all", "0_0"},
+			{"art", "1_0"},
+			{"art-anime", "1_1"},
+			{"art-doujinshi", "1_2"},
+			{"art-games", "1_3"},
+			{"art-manga", "1_4"},
+			{"art-pictures", "1_5"},
+			{"real-life", "2_0"},
+			{"real-life-photos", "2_1"},
+			{"real-life-videos", "2_2"},
+		},
+	}
+
+	sortKeys = []searchOption{
+		{"date", "id"},
+		{"downloads", "downloads"},
+		{"size", "size"},
+		{"seeders", "seeders"},
+		{"leechers", "leechers"},
+		{"comments", "comments"},
+	}
+
+	filters = []searchOption{
+		{"no-filter", "0"},
+		{"no-remakes", "1"},
+		{"trusted-only", "2"},
+	}
+
+	orders = []searchOption{
+		{"desc", "desc"},
+		{"asc", "asc"},
+	}
+)
+
+// Categories returns the categories of the provider.
+func Categories(provider string) []string {
+	return optionNames(categories[provider])
+}
+
+// SortKeys returns what the searches can be sorted by.
+func SortKeys() []string {
+	return optionNames(sortKeys)
+}
+
+// Filters returns the filters of the searches.
+func Filters() []string {
+	return optionNames(filters)
+}
+
+// Orders returns the sort orders of the searches.
+func Orders() []string {
+	return optionNames(orders)
+}
+
+func optionNames(options []searchOption) []string {
+	names := make([]string, len(options))
+	for i, option := range options {
+		names[i] = option.name
+	}
+
+	return names
+}
+
+func optionCode(options []searchOption, name string) (string, bool) {
+	for _, option := range options {
+		if option.name == name {
+			return option.code, true
+		}
+	}
+
+	return "", false
+}
+
+// Search returns the results of the RSS feed of the provider, the way go-nyaa
+// does but through the shared HTTP client.
+func Search(opts nyaa.SearchOptions) ([]types.Torrent, error) {
+	feedURL, err := SearchURL(opts, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return FetchFeed(feedURL)
+}
+
+// SearchURL returns the URL of the RSS feed of the search, among the torrents
+// of the submitter when it isn't empty.
+func SearchURL(opts nyaa.SearchOptions, submitter string) (string, error) {
+	host := ProviderHost(opts.Provider)
+	if host == "" {
+		return "", fmt.Errorf("unknown provider %q, available options: nyaa, sukebei", opts.Provider)
+	}
+
+	query := url.Values{}
+	query.Set("page", "rss")
+	query.Set("q", opts.Query)
+
+	if submitter != "" {
+		query.Set("u", submitter)
+	}
+
+	if opts.Category != "" {
+		code, ok := optionCode(categories[opts.Provider], opts.Category)
+		if !ok {
+			return "", fmt.Errorf("unknown %s category %q", opts.Provider, opts.Category)
+		}
+		query.Set("c", code)
+	}
+
+	if opts.SortBy != "" {
+		code, ok := optionCode(sortKeys, opts.SortBy)
+		if !ok {
+			return "", fmt.Errorf("unknown sort %q", opts.SortBy)
+		}
+		query.Set("s", code)
+	}
+
+	order := "desc"
+	if opts.OrderBy != "" {
+		var ok bool
+		order, ok = optionCode(orders, opts.OrderBy)
+		if !ok {
+			return "", fmt.Errorf("unknown order %q", opts.OrderBy)
+		}
+	}
+	query.Set("o", order)
+
+	if opts.Filter != "" {
+		code, ok := optionCode(filters, opts.Filter)
+		if !ok {
+			return "", fmt.Errorf("unknown filter %q", opts.Filter)
+		}
+		query.Set("f", code)
+	}
+
+	return fmt.Sprintf("https://%s/?%s", host, query.Encode()), nil
+}
+
+// FetchFeed returns the torrents of an RSS feed of nyaa.
+func FetchFeed(feedURL string) ([]types.Torrent, error) {
+	res, err := Get(feedURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	feed, err := gofeed.NewParser().Parse(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var torrents []types.Torrent
+	for _, item := range feed.Items {
+		torrents = append(torrents, types.Torrent{
+			Name:        item.Title,
+			Link:        item.Link,
+			Date:        item.Published,
+			Description: item.Description,
+			GUID:        item.GUID,
+			Comments:    nyaaExtension(item, "comments"),
+			IsTrusted:   nyaaExtension(item, "trusted"),
+			IsRemake:    nyaaExtension(item, "remake"),
+			Size:        nyaaExtension(item, "size"),
+			Seeders:     nyaaExtension(item, "seeders"),
+			Leechers:    nyaaExtension(item, "leechers"),
+			Downloads:   nyaaExtension(item, "downloads"),
+			Category:    nyaaExtension(item, "category"),
+			CategoryID:  nyaaExtension(item, "categoryId"),
+			InfoHash:    nyaaExtension(item, "infoHash"),
+		})
+	}
+
+	return torrents, nil
+}
+
+func nyaaExtension(item *gofeed.Item, name string) string {
+	values := item.Extensions["nyaa"][name]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0].Value
+}
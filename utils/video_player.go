@@ -1,6 +1,19 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// Interval between two position requests to the video player
+const positionInterval = 5 * time.Second
+
+// VideoPlayers are the supported video players
+var VideoPlayers = []string{
+	"vlc",
+	"mpv",
+}
 
 type VideoPlayerConfig struct {
 	VideoPlayer string
@@ -8,14 +21,88 @@ type VideoPlayerConfig struct {
 	Name        string
 	OnTop       bool
 	Fullscreen  bool
+
+	// Subtitles are local subtitle files, best match first
+	Subtitles []string
+
+	// StartPosition is the position in seconds to start the video at
+	StartPosition float64
+	// OnPosition is called periodically with the playback position and the
+	// duration of the video in seconds, when the player can report them
+	OnPosition func(position float64, duration float64)
+}
+
+// playerControl asks a running video player for its playback position.
+type playerControl interface {
+	Position() (position float64, duration float64, err error)
 }
 
-func getVlcArgs(config VideoPlayerConfig) []string {
-	return []string{
-		"-q",
-		"--video-on-top",
-		"--play-and-exit",
-		fmt.Sprintf("--meta-title=\"%s\"", config.Name),
-		config.Url,
+func RunVideoPlayer(config VideoPlayerConfig) error {
+	videoPlayerPath, err := findVideoPlayer(config.VideoPlayer)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	var control playerControl
+
+	switch config.VideoPlayer {
+	case "vlc":
+		args, control, err = getVlcArgs(config)
+	case "mpv":
+		args, control = getMpvArgs(config)
+	default:
+		return fmt.Errorf("unsupported video player %q", config.VideoPlayer)
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(videoPlayerPath, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	// OnPosition is never called once RunVideoPlayer returned, so the caller
+	// can use the last position without racing with it
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	if config.OnPosition != nil {
+		go func() {
+			defer close(stopped)
+			watchPosition(control, config.OnPosition, done)
+		}()
+	} else {
+		close(stopped)
+	}
+
+	err = cmd.Wait()
+	close(done)
+	<-stopped
+
+	if err != nil {
+		if _, isExitError := err.(*exec.ExitError); !isExitError {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func watchPosition(control playerControl, onPosition func(float64, float64), done <-chan struct{}) {
+	ticker := time.NewTicker(positionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			// The control interface isn't available while the player starts
+			position, duration, err := control.Position()
+			if err == nil {
+				onPosition(position, duration)
+			}
+		}
 	}
 }
@@ -4,22 +4,20 @@ import (
 	"os/exec"
 )
 
-func RunVideoPlayer(config VideoPlayerConfig) error {
-	videoPlayerPath, err := exec.LookPath("vlc")
-	if err != nil {
-		videoPlayerPath = `/Applications/VLC.app/Contents/MacOS/VLC`
-	}
-
-	err = exec.Command(
-		videoPlayerPath,
-		getVlcArgs(config)...,
-	).Run()
+var applicationPaths = map[string]string{
+	"vlc": `/Applications/VLC.app/Contents/MacOS/VLC`,
+	"mpv": `/Applications/mpv.app/Contents/MacOS/mpv`,
+}
 
+func findVideoPlayer(videoPlayer string) (string, error) {
+	videoPlayerPath, err := exec.LookPath(videoPlayer)
 	if err != nil {
-		if _, isExitError := err.(*exec.ExitError); !isExitError {
-			return err
+		if applicationPath, ok := applicationPaths[videoPlayer]; ok {
+			return applicationPath, nil
 		}
+
+		return "", err
 	}
 
-	return nil
+	return videoPlayerPath, nil
 }
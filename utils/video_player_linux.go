@@ -4,22 +4,6 @@ import (
 	"os/exec"
 )
 
-func RunVideoPlayer(config VideoPlayerConfig) error {
-	videoPlayerPath, err := exec.LookPath("vlc")
-	if err != nil {
-		return err
-	}
-
-	err = exec.Command(
-		videoPlayerPath,
-		getVlcArgs(config)...,
-	).Run()
-
-	if err != nil {
-		if _, isExitError := err.(*exec.ExitError); !isExitError {
-			return err
-		}
-	}
-
-	return nil
+func findVideoPlayer(videoPlayer string) (string, error) {
+	return exec.LookPath(videoPlayer)
 }
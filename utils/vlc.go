@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// vlcControl reads the playback position from the VLC HTTP interface.
+type vlcControl struct {
+	address  string
+	password string
+	client   *http.Client
+}
+
+func getVlcArgs(config VideoPlayerConfig) ([]string, playerControl, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	password := make([]byte, 16)
+	if _, err := rand.Read(password); err != nil {
+		return nil, nil, err
+	}
+
+	control := &vlcControl{
+		address:  fmt.Sprintf("localhost:%d", port),
+		password: hex.EncodeToString(password),
+		client:   &http.Client{Timeout: 2 * time.Second},
+	}
+
+	args := []string{
+		"-q",
+		"--play-and-exit",
+		"--extraintf", "http",
+		"--http-host", "localhost",
+		"--http-port", strconv.Itoa(port),
+		"--http-password", control.password,
+		fmt.Sprintf("--meta-title=%s", config.Name),
+	}
+
+	if config.OnTop {
+		args = append(args, "--video-on-top")
+	}
+
+	if config.Fullscreen {
+		args = append(args, "--fullscreen")
+	}
+
+	if config.StartPosition > 0 {
+		args = append(args, fmt.Sprintf("--start-time=%.0f", config.StartPosition))
+	}
+
+	// VLC only takes a single subtitle file
+	if len(config.Subtitles) > 0 {
+		args = append(args, "--sub-file="+config.Subtitles[0])
+	}
+
+	return append(args, config.Url), control, nil
+}
+
+func (c *vlcControl) Position() (float64, float64, error) {
+	req, err := http.NewRequest("GET", "http://"+c.address+"/requests/status.json", nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.SetBasicAuth("", c.password)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("vlc: %s", res.Status)
+	}
+
+	var status struct {
+		Time   float64 `json:"time"`
+		Length float64 `json:"length"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		return 0, 0, err
+	}
+
+	return status.Time, status.Length, nil
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func fakeVlc(t *testing.T, handler http.HandlerFunc) *vlcControl {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &vlcControl{
+		address:  strings.TrimPrefix(server.URL, "http://"),
+		password: "secret",
+		client:   server.Client(),
+	}
+}
+
+func TestVlcPosition(t *testing.T) {
+	control := fakeVlc(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/requests/status.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"state":"playing","time":754,"length":1420,"volume":256}`))
+	})
+
+	position, duration, err := control.Position()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if position != 754 || duration != 1420 {
+		t.Errorf("got position %v and duration %v, want 754 and 1420", position, duration)
+	}
+}
+
+func TestVlcErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "wrong password",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+		},
+		{
+			name: "invalid json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`<html>`))
+			},
+		},
+		{
+			name: "connection closed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					conn.Close()
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			control := fakeVlc(t, test.handler)
+
+			if _, _, err := control.Position(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestVlcNotRunning(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	control := &vlcControl{
+		address: strings.TrimPrefix(server.URL, "http://"),
+		client:  http.DefaultClient,
+	}
+
+	if _, _, err := control.Position(); err == nil {
+		t.Error("expected an error when VLC isn't listening")
+	}
+}
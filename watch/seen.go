@@ -0,0 +1,67 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+// Seen records the GUIDs of the releases already handled by the watcher so
+// nothing is downloaded twice.
+type Seen struct {
+	GUIDs map[string]time.Time `json:"guids"`
+
+	path string
+}
+
+func LoadSeen() (*Seen, error) {
+	directory, err := utils.ConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Seen{
+		GUIDs: make(map[string]time.Time),
+		path:  filepath.Join(directory, "watch-seen.json"),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	if s.GUIDs == nil {
+		s.GUIDs = make(map[string]time.Time)
+	}
+
+	return s, nil
+}
+
+func (s *Seen) Has(guid string) bool {
+	_, ok := s.GUIDs[guid]
+	return ok
+}
+
+func (s *Seen) Add(guid string) {
+	s.GUIDs[guid] = time.Now()
+}
+
+func (s *Seen) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
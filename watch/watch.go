@@ -0,0 +1,206 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/quantumsheep/go-nyaa/v2/nyaa"
+	"github.com/quantumsheep/go-nyaa/v2/types"
+	"github.com/quantumsheep/nyaa-cli/config"
+	"github.com/quantumsheep/nyaa-cli/engine"
+	"github.com/quantumsheep/nyaa-cli/library"
+	"github.com/quantumsheep/nyaa-cli/parser"
+	"github.com/quantumsheep/nyaa-cli/utils"
+)
+
+type rule struct {
+	config.WatchRule
+
+	nameRegex *regexp.Regexp
+}
+
+type Watcher struct {
+	Provider string
+	// When set, matching releases are marked as seen without being downloaded
+	MarkOnly bool
+
+	rules   []*rule
+	seen    *Seen
+	engine  *engine.Engine
+	library *library.Library
+}
+
+func NewWatcher(cfg *config.Config) (*Watcher, error) {
+	if len(cfg.Watch) == 0 {
+		return nil, fmt.Errorf("no watch rules are defined in the configuration file")
+	}
+
+	w := &Watcher{
+		Provider: cfg.Provider,
+	}
+
+	for _, r := range cfg.Watch {
+		compiled := &rule{WatchRule: r}
+
+		if r.NameRegex != "" {
+			var err error
+			compiled.nameRegex, err = regexp.Compile(r.NameRegex)
+			if err != nil {
+				return nil, fmt.Errorf("watch rule %q: %w", r.Name, err)
+			}
+		}
+
+		w.rules = append(w.rules, compiled)
+	}
+
+	var err error
+	w.seen, err = LoadSeen()
+	if err != nil {
+		return nil, err
+	}
+
+	w.library, err = library.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	directory, err := filepath.Abs(cfg.OutputDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	w.engine, err = engine.NewEngine(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	// A dead release is retried on the next check instead of blocking the
+	// next ones
+	w.engine.StallTimeout, err = cfg.StallTimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Run checks the feeds every interval until an error occurs. A zero interval
+// checks them only once.
+func (w *Watcher) Run(interval time.Duration) error {
+	for {
+		if err := w.Check(); err != nil {
+			return err
+		}
+
+		if interval == 0 {
+			return nil
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func (w *Watcher) Check() error {
+	for _, r := range w.rules {
+		torrents, err := w.fetch(r)
+		if err != nil {
+			log.Printf("watch rule %q: %v", r.Name, err)
+			continue
+		}
+
+		for _, torrent := range torrents {
+			if w.seen.Has(torrent.GUID) || !r.matches(torrent) {
+				continue
+			}
+
+			if !w.MarkOnly {
+				log.Printf("[%s] downloading %s", r.Name, torrent.Name)
+
+				if err := w.download(torrent); err != nil {
+					log.Printf("[%s] %s: %v", r.Name, torrent.Name, err)
+					continue
+				}
+			}
+
+			w.seen.Add(torrent.GUID)
+			if err := w.seen.Save(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Watcher) download(torrent types.Torrent) error {
+	if err := w.engine.SetTorrentFromPath(torrent.Link); err != nil {
+		return err
+	}
+	defer w.engine.DropCurrentTorrent()
+
+	if err := w.engine.DownloadAll(context.Background()); err != nil {
+		return err
+	}
+
+	for i, path := range w.engine.FilePaths() {
+		if !library.IsVideo(path) {
+			continue
+		}
+
+		localPath := w.engine.LocalPath(i)
+
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return err
+		}
+
+		item := library.NewItem(localPath, info.Size())
+		item.GUID = torrent.GUID
+		item.InfoHash = w.engine.InfoHash()
+
+		w.library.Add(item)
+	}
+
+	return w.library.Save()
+}
+
+func (w *Watcher) fetch(r *rule) ([]types.Torrent, error) {
+	filter := "no-filter"
+	if r.TrustedOnly {
+		filter = "trusted-only"
+	}
+
+	feedURL, err := utils.SearchURL(nyaa.SearchOptions{
+		Provider: w.Provider,
+		Query:    r.Query,
+		Category: r.Category,
+		Filter:   filter,
+	}, r.Submitter)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.FetchFeed(feedURL)
+}
+
+func (r *rule) matches(torrent types.Torrent) bool {
+	if r.TrustedOnly && torrent.IsTrusted != "Yes" {
+		return false
+	}
+
+	if r.nameRegex != nil && !r.nameRegex.MatchString(torrent.Name) {
+		return false
+	}
+
+	if r.Resolution != "" && !strings.EqualFold(parser.Parse(torrent.Name).Resolution, r.Resolution) {
+		return false
+	}
+
+	return true
+}